@@ -2,12 +2,11 @@ package cmd
 
 import (
 	"algo/internal/db"
+	"algo/internal/importer"
 	"algo/internal/model"
-	"errors"
+	"algo/internal/service"
 	"fmt"
 	"github.com/spf13/cobra"
-	"gorm.io/gorm"
-	"strconv"
 	"strings"
 )
 
@@ -27,72 +26,54 @@ func InitAddCmd() *cobra.Command {
 	addCmd.Flags().BoolP("debug", "D", false, "Debug mode")
 	addCmd.Flags().StringP("score", "s", "", "[ 题目评分 ] Problem score")
 	addCmd.Flags().StringP("contest", "e", "", "[ 题目竞赛 ] Problem contest")
-	addCmd.Flags().StringP("contestType", "E", "", "[ 题目竞赛类型 ] Problem contest type")
+	addCmd.Flags().StringP("contestType", "E", "", "[ 题目竞赛类型 ] Problem contest type ("+strings.Join(service.ContestTypes(), "|")+")")
+	addCmd.Flags().StringP("from", "F", "", "[ 从题目链接导入标题、难度、标签与题面 ] Import title, difficulty, tags and description from a problem URL")
+	addCmd.Flags().String("status", "", "[ 做题状态，默认 solved，todo 可以不提供代码 ] Problem status (todo|attempted|solved|needs-review), defaults to solved, code is optional for todo")
+	addCmd.Long = `Add a new problem, missing fields are prompted. Problems added with
+--status todo form a backlog and need no code until they are solved.
+Example:
+  algo add -t "Two Sum" -d easy -g array,hash -c ./two-sum.cpp
+  algo add --from https://leetcode.com/problems/two-sum/ -c ./two-sum.cpp
+  algo add --from https://codeforces.com/contest/1/problem/A --status todo`
 	return addCmd
 }
 
 func addProblem(cmd *cobra.Command, args []string) {
 	debug, _ := cmd.Flags().GetBool("debug")
+	var imported *importer.Problem
+	if from := cmd.Flag("from").Value.String(); from != "" {
+		var err error
+		if imported, err = importProblem(cmd, from); err != nil {
+			fmt.Println("Failed to import problem:", err)
+			return
+		}
+	}
 	title, difficulty, tags, solution, note, codePath, score, contest, contestType := getAddCmdParams(cmd, debug)
 
-	diff := model.Difficulty(difficulty)
-	valid := diff.Valid()
-	if !valid {
-		panic("invalid difficulty")
+	parsedScore, err := service.ParseScore(score)
+	if err != nil {
+		fmt.Println("Failed to add problem:", err)
+		return
 	}
-
-	conn := db.GetDB(debug)
-	err := conn.Transaction(func(tx *gorm.DB) error {
-		tagsArr, err := CheckTags(tx, tags)
-		if err != nil {
-			return err
-		}
-		var count int64
-		tx.Model(&model.Problem{}).Select("max(id)").Scan(&count)
-		count++
-		problem := &model.Problem{
-			ID:          count,
-			Title:       title,
-			Difficulty:  diff,
-			Tags:        tagsArr,
-			SolutionURL: solution,
-			Note:        note,
-			CodePath:    codePath,
-		}
-		if scoreInt, err := strconv.Atoi(score); err == nil {
-			if scoreInt >= 0 && scoreInt <= 255 { // uint8 范围检查
-				v := uint8(scoreInt)
-				problem.Score = &v
-			}
-		}
-		problem.SetSlug()
-		problem.SetCodePath()
-		if err := tx.Create(&problem).Error; err != nil {
-			return err
-		}
-		if contest != "" {
-			cts := &model.Contest{}
-			if err = tx.Where("title = ?", contest).Preload("Problems").First(cts).Error; err != nil {
-				if errors.Is(err, gorm.ErrRecordNotFound) {
-					ct, _ := model.GetContestType(contestType)
-					tx.Model(&model.Contest{}).Select("max(id)").First(&count)
-					count++
-
-					cts = &model.Contest{
-						ID:       count,
-						Title:    contest,
-						Type:     ct,
-						Problems: []*model.Problem{},
-					}
-				}
-			}
-
-			cts.Problems = append(cts.Problems, problem)
-			tx.Save(cts)
+	in := &service.AddInput{
+		Title:       title,
+		Difficulty:  difficulty,
+		Tags:        service.SplitTags(tags),
+		SolutionURL: solution,
+		Note:        note,
+		CodePath:    codePath,
+		Score:       parsedScore,
+		Contest:     contest,
+		ContestType: contestType,
+		Status:      cmd.Flag("status").Value.String(),
+	}
+	if imported != nil {
+		in.Description = imported.Description
+		if imported.Rating > 0 {
+			in.Rating = &imported.Rating
 		}
-
-		return nil
-	})
+	}
+	_, err = service.New(db.GetDB(debug)).AddProblem(cmd.Context(), in)
 
 	if err != nil {
 		fmt.Println("Failed to add problem:", err)
@@ -101,36 +82,35 @@ func addProblem(cmd *cobra.Command, args []string) {
 	}
 }
 
-func CheckTags(tx *gorm.DB, tags string) ([]*model.Tag, error) {
-	var count int64
-	split := strings.Split(tags, ",")
-	tagsArr := make([]*model.Tag, 0, len(split))
-	for _, tagName := range split {
-		tagName = strings.TrimSpace(tagName)
-		if tagName == "" {
-			continue
-		}
-		tagName = strings.ToLower(tagName)
-
-		tx.Model(&model.Tag{}).Select("max(id)").Scan(&count)
-		var tag model.Tag
-		if err := tx.Where("name = ?", tagName).First(&tag).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				count++
-				tag = model.Tag{
-					ID:   count,
-					Name: tagName,
-				}
-				if err = tx.Create(&tag).Error; err != nil {
-					return nil, err
-				}
-			} else {
+// importProblem 从在线题库获取题目，未在命令行中指定的参数使用导入的值
+func importProblem(cmd *cobra.Command, from string) (*importer.Problem, error) {
+	provider, err := importer.ForURL(from)
+	if err != nil {
+		return nil, err
+	}
+	problem, err := provider.FetchProblem(cmd.Context(), from)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]string{
+		"title":      problem.Title,
+		"difficulty": problem.Difficulty,
+		"tags":       strings.Join(problem.Tags, ","),
+		"solution":   problem.URL,
+	}
+	// 竞赛名称与类型需同时使用导入的值，避免把题目挂到同名的其他竞赛
+	if !cmd.Flags().Changed("contest") && problem.Contest != "" {
+		values["contest"] = problem.Contest
+		values["contestType"] = problem.ContestType
+	}
+	for name, value := range values {
+		if !cmd.Flags().Changed(name) && value != "" {
+			if err = cmd.Flags().Set(name, value); err != nil {
 				return nil, err
 			}
 		}
-		tagsArr = append(tagsArr, &tag)
 	}
-	return tagsArr, nil
+	return problem, nil
 }
 
 func getAddCmdParams(cmd *cobra.Command, debug bool) (string, string, string, string, string, string, string, string, string) {
@@ -139,12 +119,16 @@ func getAddCmdParams(cmd *cobra.Command, debug bool) (string, string, string, st
 	tags := getCmdParam(cmd, "tags", "Tags: ", debug)
 	solution := getCmdParam(cmd, "solution", "Solution URL: ", debug)
 	note := getCmdParam(cmd, "note", "Note: ", debug)
-	codePath := getCmdParam(cmd, "codePath", "Code Path: ", debug)
+	codePath := cmd.Flag("codePath").Value.String()
+	// 待做的题目可以没有代码，不再提示输入
+	if !strings.EqualFold(strings.TrimSpace(cmd.Flag("status").Value.String()), string(model.StatusTodo)) {
+		codePath = getCmdParam(cmd, "codePath", "Code Path: ", debug)
+	}
 	score := getCmdParam(cmd, "score", "Score: ", debug)
 	contest := cmd.Flag("contest").Value.String()
 	contestType := ""
 	if contest != "" {
-		contestType = getCmdParam(cmd, "contestType", "ContestType:", debug)
+		contestType = getCmdParam(cmd, "contestType", "ContestType ("+strings.Join(service.ContestTypes(), "|")+"): ", debug)
 	}
 	return title, difficulty, tags, solution, note, codePath, score, contest, contestType
 }
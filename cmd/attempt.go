@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"algo/internal/db"
+	"algo/internal/model"
+	"algo/internal/service"
+	"fmt"
+	"github.com/spf13/cobra"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var attemptCmd = &cobra.Command{
+	Use:   "attempt [slug]",
+	Short: "[ 记录一次做题尝试 ] Record an attempt at a problem",
+	Args:  cobra.ExactArgs(1),
+	Run:   recordAttempt,
+}
+
+func InitAttemptCmd() *cobra.Command {
+	attemptCmd.Flags().BoolP("debug", "D", false, "Debug mode")
+	attemptCmd.Flags().StringP("verdict", "v", "", "[ 提交结果 ] Verdict ("+strings.Join(model.AttemptVerdicts, "|")+")")
+	attemptCmd.Flags().StringP("time", "t", "", "[ 耗时，如 45m、1h30m，纯数字为分钟 ] Time spent, e.g. 45m or 1h30m, plain numbers are minutes")
+	attemptCmd.Flags().StringP("language", "l", "", "[ 语言，默认按代码文件推断 ] Language, inferred from the code file by default")
+	attemptCmd.Flags().StringP("code", "c", "", "[ 保存为快照的代码文件，默认为题目代码 ] Code file to snapshot, defaults to the problem's code")
+	attemptCmd.Flags().StringP("note", "n", "", "[ 简短备注 ] Short note")
+	attemptCmd.Flags().Bool("list", false, "[ 列出题目的全部尝试 ] List all attempts of the problem")
+	attemptCmd.Long = `Record an attempt at a problem with its verdict, time spent, language and a
+snapshot of the code, which is copied to CodeDir next to the problem's code.
+Example:
+  algo attempt 0001_two-sum -v WA -t 25m -n "forgot overflow"
+  algo attempt 0001_two-sum -v AC -t 40m
+  algo attempt 0001_two-sum --list`
+	return attemptCmd
+}
+
+func recordAttempt(cmd *cobra.Command, args []string) {
+	debug, _ := cmd.Flags().GetBool("debug")
+	svc := service.New(db.GetDB(debug))
+	if list, _ := cmd.Flags().GetBool("list"); list {
+		attempts, err := svc.ListAttempts(cmd.Context(), args[0])
+		if err != nil {
+			fmt.Println("Failed to list attempts:", err)
+			return
+		}
+		printAttempts(attempts)
+		return
+	}
+
+	verdict := getCmdParam(cmd, "verdict", "Verdict ("+strings.Join(model.AttemptVerdicts, "|")+"): ", debug)
+	duration, err := parseSpent(cmd.Flag("time").Value.String())
+	if err != nil {
+		fmt.Println("Failed to record attempt:", err)
+		return
+	}
+	attempt, err := svc.AddAttempt(cmd.Context(), args[0], &service.AttemptInput{
+		Verdict:  verdict,
+		Duration: duration,
+		Language: cmd.Flag("language").Value.String(),
+		CodePath: cmd.Flag("code").Value.String(),
+		Note:     cmd.Flag("note").Value.String(),
+	})
+	if err != nil {
+		fmt.Println("Failed to record attempt:", err)
+		return
+	}
+	fmt.Printf("Recorded %s attempt of %s\n", attempt.Verdict, args[0])
+}
+
+// parseSpent 解析耗时，纯数字按分钟计算
+func parseSpent(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if minutes, err := strconv.Atoi(s); err == nil {
+		return time.Duration(minutes) * time.Minute, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, e.g. 45m or 1h30m", s)
+	}
+	return d, nil
+}
+
+func printAttempts(attempts []*model.Attempt) {
+	fmt.Println("Total attempts:", len(attempts))
+	for i, a := range attempts {
+		fmt.Printf("[%d] %s | %-3s | Time: %s | Language: %s", i+1, a.CreatedAt.Local().Format("2006-01-02 15:04"), a.Verdict, a.DurationText(), a.Language)
+		if a.Note != "" {
+			fmt.Printf(" | %s", a.Note)
+		}
+		fmt.Println()
+		if a.CodePath != "" {
+			fmt.Println("Code:", a.CodePath)
+		}
+	}
+}
+
+// attemptSummary 列表中展示的尝试次数与各次结果
+func attemptSummary(attempts []*model.Attempt) string {
+	verdicts := make([]string, len(attempts))
+	for i, a := range attempts {
+		verdicts[i] = a.Verdict
+	}
+	return fmt.Sprintf("%d (%s)", len(attempts), strings.Join(verdicts, " → "))
+}
@@ -1,11 +1,9 @@
 package cmd
 
 import (
-	"algo/internal/model"
 	"bufio"
 	"fmt"
 	"github.com/spf13/cobra"
-	"gorm.io/gorm"
 	"os"
 	"strings"
 )
@@ -47,16 +45,3 @@ func Scanln() string {
 
 	return strings.Join(lines, "")
 }
-
-func clearProblemTags(tx *gorm.DB, problem *model.Problem) error {
-	// 先预加载 Tags
-	if err := tx.Preload("Tags").First(problem, problem.ID).Error; err != nil {
-		return fmt.Errorf("failed to load problem tags: %w", err)
-	}
-
-	// 清空多对多关联
-	if err := tx.Model(problem).Association("Tags").Clear(); err != nil {
-		return fmt.Errorf("failed to clear tags association: %w", err)
-	}
-	return nil
-}
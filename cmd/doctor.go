@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"algo/internal/runner"
+	"fmt"
+	"github.com/spf13/cobra"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [ext...]",
+	Short: "[ 检查各语言的编译与运行环境 ] Check that language toolchains are installed",
+	Run:   doctor,
+}
+
+func InitDoctorCmd() *cobra.Command {
+	doctorCmd.Long = `Check that the compilers and interpreters used by [LANGUAGES.<ext>] in algo.toml
+and the built-in languages can be found in PATH.
+Example:
+  algo doctor
+  algo doctor cpp py`
+	return doctorCmd
+}
+
+func doctor(cmd *cobra.Command, args []string) {
+	missing := 0
+	for _, lang := range runner.Languages() {
+		if len(args) > 0 && !slices.ContainsFunc(args, func(ext string) bool {
+			return strings.EqualFold(strings.TrimPrefix(ext, "."), lang.Ext)
+		}) {
+			continue
+		}
+		var found, notFound []string
+		for _, tool := range lang.Tools() {
+			if path, err := exec.LookPath(tool); err == nil {
+				found = append(found, path)
+			} else {
+				notFound = append(notFound, tool)
+			}
+		}
+		status := "OK"
+		if len(notFound) > 0 {
+			status = "MISSING " + strings.Join(notFound, ", ")
+			missing++
+		}
+		fmt.Printf("%-6s %-12s %-24s %s\n", lang.Ext, lang.Name, status, strings.Join(found, ", "))
+	}
+	if missing > 0 {
+		fmt.Printf("%d language(s) unavailable\n", missing)
+	} else {
+		fmt.Println("All toolchains found")
+	}
+}
@@ -3,10 +3,9 @@ package cmd
 import (
 	"algo/internal/db"
 	"algo/internal/model"
+	"algo/internal/service"
 	"fmt"
 	"github.com/spf13/cobra"
-	"gorm.io/gorm"
-	"strconv"
 )
 
 var editCmd = &cobra.Command{
@@ -19,7 +18,11 @@ var editCmd = &cobra.Command{
 func InitEditCmd() *cobra.Command {
 	editCmd.Long = `Edit a problem by its slug.
 Example:
-  algo edit two-sum --debug`
+  algo edit two-sum --debug
+  algo edit 0001_two-sum --checker float --eps 1e-9
+  algo edit 0001_two-sum --checker-file checker.cpp
+  algo edit 0001_two-sum --status needs-review
+  algo edit 1900_guess --no-interactor`
 
 	editCmd.Flags().StringP("title", "t", "", "[ 题目标题 ] Problem title")
 	editCmd.Flags().StringP("difficulty", "d", "", "[ 题目难度 ] Problem difficulty (easy|medium|hard)")
@@ -29,6 +32,11 @@ Example:
 	editCmd.Flags().StringP("codePath", "c", "", "[ 题目代码本地地址 ] Problem code path")
 	editCmd.Flags().BoolP("debug", "D", false, "Debug mode")
 	editCmd.Flags().StringP("score", "s", "", "[ 题目评分 ] Problem score")
+	editCmd.Flags().String("checker", "", "[ 答案检查方式 ] Checker mode (exact|tokens|float|custom)")
+	editCmd.Flags().Float64("eps", 0, "[ float 模式的绝对/相对误差，默认 1e-6 ] Absolute/relative epsilon of the float checker, defaults to 1e-6")
+	editCmd.Flags().String("checker-file", "", "[ testlib 风格的检查程序，保存到 CodeDir ] testlib-style checker source, saved to CodeDir")
+	editCmd.Flags().String("status", "", "[ 做题状态 ] Problem status (todo|attempted|solved|needs-review)")
+	editCmd.Flags().Bool("no-interactor", false, "[ 删除交互器，不再作为交互题测试 ] Remove the interactor so the problem is no longer tested interactively")
 
 	return editCmd
 }
@@ -43,68 +51,50 @@ func editProblem(cmd *cobra.Command, args []string) {
 	note := cmd.Flag("note").Value.String()
 	codePath := cmd.Flag("codePath").Value.String()
 	score := cmd.Flag("score").Value.String()
+	checker := cmd.Flag("checker").Value.String()
+	checkerFile := cmd.Flag("checker-file").Value.String()
+	var eps *float64
+	if cmd.Flags().Changed("eps") {
+		v, _ := cmd.Flags().GetFloat64("eps")
+		eps = &v
+	}
 
 	// 获取 slug
 	slug := args[0]
 
-	conn := db.GetDB(debug)
-	_ = conn.Transaction(func(tx *gorm.DB) error {
-		var problem model.Problem
-		if err := tx.Where("slug = ?", slug).First(&problem).Error; err != nil {
-			return fmt.Errorf("problem not found: %w", err)
-		}
-
-		if title != "" {
-			problem.Title = title
-			problem.SetSlug()
-		}
-		if difficulty != "" {
-			diff := model.Difficulty(difficulty)
-			if !diff.Valid() {
-				return fmt.Errorf("invalid difficulty")
-			}
-			problem.Difficulty = diff
-		}
-		if tags != "" {
-			checkTags, err := CheckTags(tx, tags)
-			if err != nil {
-				return err
-			}
-			if err = clearProblemTags(tx, &problem); err != nil {
-				return fmt.Errorf("failed to clear old tags: %w", err)
-			}
-			// 重新添加新标签
-			if err = tx.Model(&problem).Association("Tags").Replace(checkTags); err != nil {
-				return fmt.Errorf("failed to update tags: %w", err)
-			}
-			problem.Tags = checkTags
-		}
-		if solution != "" {
-			problem.SolutionURL = solution
-		}
-		if note != "" {
-			problem.Note = note
-		}
-		if codePath != "" {
-			problem.CodePath = codePath
-			problem.SetCodePath()
+	parsedScore, err := service.ParseScore(score)
+	if err != nil {
+		fmt.Println("Failed to edit problem:", err)
+		return
+	}
+	svc := service.New(db.GetDB(debug))
+	problem, err := svc.EditProblem(cmd.Context(), slug, &service.EditInput{
+		Title:       title,
+		Difficulty:  difficulty,
+		Tags:        service.SplitTags(tags),
+		SolutionURL: solution,
+		Note:        note,
+		CodePath:    codePath,
+		Score:       parsedScore,
+		Checker:     checker,
+		Epsilon:     eps,
+		CheckerPath: checkerFile,
+		Status:      cmd.Flag("status").Value.String(),
+	})
+	if err != nil {
+		fmt.Println("Failed to edit problem:", err)
+		return
+	}
+	if noInteractor, _ := cmd.Flags().GetBool("no-interactor"); noInteractor {
+		path, err := svc.RemoveAux(cmd.Context(), problem.Slug, model.AuxInteractor)
+		if err != nil {
+			fmt.Println("Failed to remove interactor:", err)
+			return
 		}
-		if score != "" {
-			atoi, err := strconv.Atoi(score)
-			if err != nil {
-				return err
-			}
-			if atoi >= 0 && atoi <= 255 {
-				v := uint8(atoi)
-				problem.Score = &v
-			}
+		if path != "" {
+			fmt.Println("Removed interactor:", path)
 		}
+	}
 
-		if err := tx.Save(&problem).Error; err != nil {
-			return fmt.Errorf("failed to edit problem: %w", err)
-		}
-
-		fmt.Println("Problem edited successfully")
-		return nil
-	})
+	fmt.Println("Problem edited successfully")
 }
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"algo/internal/db"
+	"algo/internal/exporter"
+	"algo/internal/service"
+	"algo/pkg/config"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "[ 导出题目 ] Export problems to other formats",
+}
+
+var exportObsidianCmd = &cobra.Command{
+	Use:   "obsidian",
+	Args:  cobra.NoArgs,
+	Short: "[ 导出为 Obsidian 笔记库 ] Export problems as an Obsidian vault",
+	Run:   exportObsidian,
+}
+
+func InitExportCmd() *cobra.Command {
+	exportObsidianCmd.Flags().BoolP("debug", "D", false, "Debug mode")
+	exportObsidianCmd.Flags().StringP("dir", "o", "", "[ 导出目录，默认为 notes_dir ] Vault directory, defaults to notes_dir")
+	exportObsidianCmd.Flags().Bool("clean", false, "[ 导出前清空旧笔记 ] Remove previously exported notes first")
+	exportObsidianCmd.Long = `Export one note per problem with YAML frontmatter, plus a hub note per tag and per contest.
+Example:
+  algo export obsidian
+  algo export obsidian --dir ~/vault/algo --clean`
+
+	exportCmd.AddCommand(exportObsidianCmd)
+	return exportCmd
+}
+
+func exportObsidian(cmd *cobra.Command, args []string) {
+	debug, _ := cmd.Flags().GetBool("debug")
+	clean, _ := cmd.Flags().GetBool("clean")
+	dir := cmd.Flag("dir").Value.String()
+	if dir == "" {
+		dir = config.GetConfig().Dir.NotesDir
+	}
+
+	svc := service.New(db.GetDB(debug))
+	problems, err := svc.PublishedProblems(cmd.Context())
+	if err != nil {
+		fmt.Println("Failed to load problems:", err)
+		return
+	}
+	contests, err := svc.ListContests(cmd.Context())
+	if err != nil {
+		fmt.Println("Failed to load contests:", err)
+		return
+	}
+
+	vault := &exporter.Obsidian{Dir: dir, Clean: clean}
+	result, err := vault.Export(problems, contests)
+	if err != nil {
+		fmt.Println("Failed to export obsidian vault:", err)
+		return
+	}
+	fmt.Printf("Exported %d problems, %d tags, %d contests to %s\n", result.Problems, result.Tags, result.Contests, dir)
+}
@@ -3,130 +3,193 @@ package cmd
 import (
 	"algo/internal/db"
 	"algo/internal/generator"
-	"algo/internal/model"
-	"algo/pkg/config"
+	"algo/internal/service"
+	"errors"
 	"fmt"
-	"github.com/flosch/pongo2"
 	"github.com/spf13/cobra"
 	"os"
 	"path/filepath"
-	"strings"
+	"runtime"
 )
 
 var genCmd = &cobra.Command{
 	Use:   "gen [slug] [type]",
-	Args:  cobra.ExactArgs(2),
+	Args:  genArgs,
 	Short: "[ 生成题目文档 ] Generate a problem markdown file",
-	Run:   getMarkdown,
+	RunE:  getMarkdown,
+	// --all 有文件生成失败时以非零状态退出，不打印用法
+	SilenceUsage: true,
+}
+
+var genHeatmapCmd = &cobra.Command{
+	Use:   "heatmap",
+	Args:  cobra.NoArgs,
+	Short: "[ 生成做题热力图 ] Generate a contribution heatmap SVG",
+	Run:   genHeatmap,
+}
+
+var genTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Args:  cobra.NoArgs,
+	Short: "[ 查看正在使用的模板 ] Show the templates in use",
+	Run:   listTemplates,
+}
+
+var genIndexCmd = &cobra.Command{
+	Use:   "index",
+	Args:  cobra.NoArgs,
+	Short: "[ 生成汇总索引 README.md ] Generate the index README.md",
+	Run:   genIndex,
 }
 
 func InitGenCmd() *cobra.Command {
 	genCmd.Flags().BoolP("debug", "D", false, "Debug mode")
+	genCmd.Flags().BoolP("all", "a", false, "[ 生成全部已解决的题目与竞赛，跳过未变化的文件 ] Generate all solved problems and contests incrementally")
+	genCmd.Flags().BoolP("force", "f", false, "[ 配合 --all 忽略缓存全部重新生成 ] With --all, regenerate even if unchanged")
+	genCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "[ 并发数 ] Number of parallel workers")
+	genCmd.Flags().StringP("template", "T", "", "[ 本次使用的模板文件 ] Template file used for this run only")
 	genCmd.Long = `Generator a problem by its slug.
 Example:
   algo gen 0001_two-sum pro --debug
   algo gen 1024 codeforces --debug 
+  algo gen --all
+  algo gen 0001_two-sum pro --template ./my-problem.md
+
+Templates are looked up in template_dir from algo.toml, see "algo gen templates".
+With --all, only solved and needs-review problems are generated, documents of
+problems moved back to todo or attempted are removed.
 `
+
+	genHeatmapCmd.Flags().BoolP("debug", "D", false, "Debug mode")
+	genHeatmapCmd.Flags().StringSlice("colors", nil, "[ 由浅到深的颜色，英文逗号分割 ] Colors from empty to busiest, comma separation")
+	genHeatmapCmd.Long = `Generate a calendar heatmap of solved problems in the last 52 weeks into the markdown dir.
+Each problem is counted on the day it was first solved; problems solved before
+this was recorded count on the day they were added.
+Example:
+  algo gen heatmap
+  algo gen heatmap --colors "#eeeeee,#c6e48b,#7bc96f,#239a3b,#196127"`
+	genCmd.AddCommand(genHeatmapCmd)
+
+	genIndexCmd.Flags().BoolP("debug", "D", false, "Debug mode")
+	genIndexCmd.Flags().Bool("no-heatmap", false, "[ 不生成热力图 ] Do not generate and embed the heatmap")
+	genIndexCmd.Flags().StringP("template", "T", "", "[ 本次使用的模板文件 ] Template file used for this run only")
+	genIndexCmd.Long = `Generate README.md in the markdown dir, linking every solved problem.
+Example:
+  algo gen index`
+	genCmd.AddCommand(genIndexCmd)
+
+	genTemplatesCmd.Flags().Bool("init", false, "[ 将内置模板写入模板目录 ] Write builtin templates into the template dir")
+	genTemplatesCmd.Long = `Show where each template (problem|contest|index|tag) is loaded from.
+Templates missing from the template dir fall back to the builtin ones.
+Example:
+  algo gen templates
+  algo gen templates --init`
+	genCmd.AddCommand(genTemplatesCmd)
 	return genCmd
 }
 
-func getMarkdown(cmd *cobra.Command, args []string) {
-	t := "pro"
-	if len(args) > 1 {
-		t = args[1]
+func genArgs(cmd *cobra.Command, args []string) error {
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		return cobra.NoArgs(cmd, args)
 	}
-	conn := db.GetDB(false)
-	if t == "pro" {
-		var problem model.Problem
-		if err := conn.Where("slug = ?", args[0]).Preload("Tags").First(&problem).Error; err != nil {
-			fmt.Println("Failed to generate problem:", err)
-			return
-		}
-		markdown, err := renderProblemMarkdown(&problem)
+	return cobra.ExactArgs(2)(cmd, args)
+}
+
+func getMarkdown(cmd *cobra.Command, args []string) error {
+	debug, _ := cmd.Flags().GetBool("debug")
+	override := cmd.Flag("template").Value.String()
+	svc := service.New(db.GetDB(debug))
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		force, _ := cmd.Flags().GetBool("force")
+		jobs, _ := cmd.Flags().GetInt("jobs")
+		result, err := svc.Generate(cmd.Context(), &service.GenerateOptions{
+			Templates: map[generator.Kind]string{generator.KindProblem: override},
+			Force:     force,
+			Jobs:      jobs,
+		})
 		if err != nil {
-			fmt.Println("Failed to render problem markdown:", err)
-			return
-		}
-		dir := config.GetConfig().Dir.MarkdownDir + "/" + problem.Difficulty.String()
-		if err = os.MkdirAll(dir, 0755); err != nil {
-			fmt.Println("Failed to create markdown dir:", err)
-			return
-		}
-		fileName := fmt.Sprintf("%s.md", problem.Slug)
-		filePath := filepath.Join(dir, fileName)
-		if err = os.WriteFile(filePath, []byte(markdown), 0644); err != nil {
-			fmt.Println("Failed to write markdown file:", err)
-			return
-		}
-	} else {
-		contest := &model.Contest{}
-		if err := conn.Where("title = ? and `type` = ?", args[0], t).Preload("Problems.Tags").First(contest).Error; err != nil {
-			fmt.Println("Failed to generate contest:", err)
-			return
+			return fmt.Errorf("failed to generate markdown: %w", err)
 		}
-		var sb strings.Builder
-		for _, problem := range contest.Problems {
-			markdown, err := renderProblemMarkdown(problem)
-			if err != nil {
-				fmt.Println("Failed to render problem markdown:", err)
-				return
-			}
-			sb.WriteString(markdown + "\n---\n")
+		for _, f := range result.Failed {
+			fmt.Printf("Failed to generate %s: %v\n", f.Path, f.Err)
 		}
-		dir := config.GetConfig().Dir.MarkdownDir + "/" + contest.Type.String()
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			fmt.Println("Failed to create markdown dir:", err)
-			return
+		for _, rel := range result.Removed {
+			fmt.Println("Removed stale markdown:", rel)
 		}
-		fileName := fmt.Sprintf("%s.md", contest.Title)
-		filePath := filepath.Join(dir, fileName)
-		if err := os.WriteFile(filePath, []byte(sb.String()), 0644); err != nil {
-			fmt.Println("Failed to write markdown file:", err)
-			return
+		fmt.Printf("Markdown files generated: %d, unchanged: %d, removed: %d, failed: %d\n",
+			result.Generated, result.Unchanged, len(result.Removed), len(result.Failed))
+		if len(result.Failed) > 0 {
+			return fmt.Errorf("failed to generate %d markdown file(s)", len(result.Failed))
 		}
+		return nil
 	}
-	fmt.Println("Markdown file generated successfully")
-}
 
-func renderProblemMarkdown(p *model.Problem) (string, error) {
-	tags := make([]string, 0)
-	for _, t := range p.Tags {
-		tags = append(tags, t.Name)
+	t := "pro"
+	if len(args) > 1 {
+		t = args[1]
 	}
-	created := p.CreatedAt.Format("2006-01-02 15:04:05")
-	updated := p.UpdatedAt.Format("2006-01-02 15:04:05")
+	var err error
+	if t == "pro" {
+		_, err = svc.GenerateProblem(cmd.Context(), args[0], map[generator.Kind]string{generator.KindProblem: override})
+	} else {
+		_, err = svc.GenerateContest(cmd.Context(), args[0], t, map[generator.Kind]string{generator.KindContest: override})
+	}
+	if err != nil {
+		fmt.Println("Failed to generate markdown:", err)
+		return nil
+	}
+	fmt.Println("Markdown file generated successfully")
+	return nil
+}
 
-	language := strings.TrimPrefix(filepath.Ext(p.CodePath), ".")
+func genHeatmap(cmd *cobra.Command, args []string) {
+	debug, _ := cmd.Flags().GetBool("debug")
+	colors, _ := cmd.Flags().GetStringSlice("colors")
 
-	data, err := os.ReadFile(p.CodePath)
+	filePath, err := service.New(db.GetDB(debug)).GenerateHeatmap(cmd.Context(), colors)
 	if err != nil {
-		return "", err
-	}
-	code := string(data)
-
-	problem := &generator.Problem{
-		Title:       p.Title,
-		Difficulty:  p.Difficulty.String(),
-		Tags:        tags,
-		SolutionURL: p.SolutionURL,
-		Score:       p.Score,
-		CreatedAt:   created,
-		UpdatedAt:   updated,
-		Slug:        p.Slug,
-		Description: p.Description,
-		Solution:    p.Note,
-		Code: &generator.Code{
-			Language: language,
-			Data:     code,
-		},
+		fmt.Println("Failed to generate heatmap:", err)
+		return
 	}
-	tpl, err := pongo2.FromString(generator.GetTemplate())
+	fmt.Println("Heatmap generated successfully:", filePath)
+}
+
+func genIndex(cmd *cobra.Command, args []string) {
+	debug, _ := cmd.Flags().GetBool("debug")
+	noHeatmap, _ := cmd.Flags().GetBool("no-heatmap")
+	override := cmd.Flag("template").Value.String()
+
+	_, err := service.New(db.GetDB(debug)).GenerateIndex(cmd.Context(),
+		map[generator.Kind]string{generator.KindIndex: override}, !noHeatmap)
 	if err != nil {
-		return "", err
+		fmt.Println("Failed to generate index:", err)
+		return
 	}
-	out, err := tpl.Execute(pongo2.Context{"problem": problem})
-	if err != nil {
-		return "", err
+	fmt.Println("Index file generated successfully")
+}
+
+func listTemplates(cmd *cobra.Command, args []string) {
+	initTemplates, _ := cmd.Flags().GetBool("init")
+	loader := service.TemplateLoader(nil)
+	for _, kind := range generator.Kinds {
+		path, _ := loader.Path(kind)
+		if initTemplates {
+			if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+				if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					fmt.Println("Failed to create template dir:", err)
+					return
+				}
+				if err = os.WriteFile(path, []byte(generator.Builtin(kind)), 0644); err != nil {
+					fmt.Println("Failed to write template:", err)
+					return
+				}
+			}
+		}
+		_, source, err := loader.Load(kind)
+		if err != nil {
+			fmt.Printf("%-8s %s (%v)\n", kind, path, err)
+			continue
+		}
+		fmt.Printf("%-8s %s\n", kind, source)
 	}
-	return out, err
 }
@@ -3,6 +3,8 @@ package cmd
 import (
 	"algo/internal/db"
 	"algo/internal/model"
+	"algo/internal/service"
+	"context"
 	"fmt"
 	"github.com/spf13/cobra"
 	"strings"
@@ -15,9 +17,7 @@ var listCmd = &cobra.Command{
 }
 
 func InitListCmd() *cobra.Command {
-	getDB := db.GetDB(false)
-	tags := make([]model.Tag, 0)
-	getDB.Model(&model.Tag{}).Find(&tags)
+	tags, _ := service.New(db.GetDB(false)).ListTags(context.Background())
 	str := make([]string, 0, len(tags))
 	for _, tag := range tags {
 		str = append(str, tag.Name)
@@ -29,6 +29,7 @@ func InitListCmd() *cobra.Command {
 		"\n"+"Example: -g tag1,tag2")
 	listCmd.Flags().BoolP("debug", "D", false, "Debug mode")
 	listCmd.Flags().StringP("score", "s", "", "[ 题目评分 ] Problem score")
+	listCmd.Flags().String("status", "", "[ 做题状态，todo 为待做清单 ] Problem status (todo|attempted|solved|needs-review), todo lists the backlog")
 	listCmd.Flags().IntP("limit", "l", 100, "[ 查询条数 ] Limit number of problems")
 	listCmd.Flags().IntP("offset", "o", 0, "[ 查询页码 ] Offset number of problems")
 	return listCmd
@@ -40,52 +41,43 @@ func listProblems(cmd *cobra.Command, args []string) {
 	difficulty := cmd.Flag("difficulty").Value.String()
 	tagsStr := cmd.Flag("tags").Value.String()
 	score := cmd.Flag("score").Value.String()
+	status := cmd.Flag("status").Value.String()
 	limit, _ := cmd.Flags().GetInt("limit")
 	offset, _ := cmd.Flags().GetInt("offset")
 
-	if limit < 0 || offset < 0 || limit > 100 {
-		fmt.Println("Invalid limit or offset")
+	parsedScore, err := service.ParseScore(score)
+	if err != nil {
+		fmt.Println("Failed to list problems:", err)
 		return
 	}
-
-	conn := db.GetDB(debug)
-	query := conn.Preload("Tags").Limit(limit).Offset(offset)
-	var problems []model.Problem
-	if difficulty != "" {
-		diff := model.Difficulty(difficulty)
-		if !diff.Valid() {
-			fmt.Println("Invalid difficulty, must be easy|medium|hard")
-			return
-		}
-		query = query.Where("difficulty = ?", diff)
-	}
-	if titleKeyword != "" {
-		query = query.Where("title LIKE ?", "%"+titleKeyword+"%")
-	}
-	if tagsStr != "" {
-		tags := strings.Split(tagsStr, ",")
-		query = query.Joins("JOIN problem_tags pt ON pt.problem_id = problems.id").
-			Joins("JOIN tags t ON t.id = pt.tag_id").
-			Where("t.name IN ?", tags)
-	}
-	if score != "" {
-		query = query.Where("score = ?", score)
-	}
-	if err := query.Order("created_at DESC").Find(&problems).Error; err != nil {
+	problems, err := service.New(db.GetDB(debug)).ListProblems(cmd.Context(), &service.ListFilter{
+		Title:      titleKeyword,
+		Difficulty: difficulty,
+		Tags:       service.SplitTags(tagsStr),
+		Score:      parsedScore,
+		Status:     status,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
 		fmt.Println("Failed to list problems:", err)
 		return
 	}
 
 	fmt.Println("Total problems:", len(problems))
 	for i, p := range problems {
-		fmt.Printf("[%d] [%s] %s | Difficulty: %s | Tags: %s\nSolution URL: %s\n",
+		fmt.Printf("[%d] [%s] %s | Difficulty: %s | Status: %s | Tags: %s\nSolution URL: %s\n",
 			i+1,
 			p.Slug,
 			p.Title,
 			p.Difficulty,
+			p.Status,
 			getTagNames(p.Tags),
 			p.SolutionURL,
 		)
+		if len(p.Attempts) > 0 {
+			fmt.Println("Attempts:", attemptSummary(p.Attempts))
+		}
 	}
 }
 
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"algo/internal/db"
+	"algo/internal/service"
+	"algo/pkg/config"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var listenCmd = &cobra.Command{
+	Use:   "listen",
+	Args:  cobra.NoArgs,
+	Short: "[ 接收 Competitive Companion 推送的题目 ] Receive problems from Competitive Companion",
+	Run:   listen,
+}
+
+func InitListenCmd() *cobra.Command {
+	listenCmd.Flags().BoolP("debug", "D", false, "Debug mode")
+	listenCmd.Flags().StringP("addr", "a", "", "[ 监听地址，默认读取配置 ] Listen address, defaults to [LISTEN] ADDR")
+	listenCmd.Flags().StringP("lang", "l", "", "[ 代码语言扩展名，默认读取配置 ] Code language extension, defaults to [LISTEN] LANGUAGE")
+	listenCmd.Flags().StringP("difficulty", "d", "", "[ 题目难度，默认读取配置 ] Problem difficulty (easy|medium|hard), defaults to [LISTEN] DIFFICULTY")
+	listenCmd.Long = `Listen for the Competitive Companion browser extension and save every received
+problem with its sample tests as todo, scaffolding a code file in CODE_DIR.
+A file named code.<lang> in TEMPLATE_DIR is used as the code template.
+Example:
+  algo listen
+  algo listen --lang py -d easy`
+	return listenCmd
+}
+
+func listen(cmd *cobra.Command, args []string) {
+	debug, _ := cmd.Flags().GetBool("debug")
+	cnf := config.GetConfig().Listen
+	addr := flagOr(cmd, "addr", cnf.Addr)
+	opts := service.CaptureOptions{
+		Language:   flagOr(cmd, "lang", cnf.Language),
+		Difficulty: flagOr(cmd, "difficulty", cnf.Difficulty),
+	}
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           newListenHandler(service.New(db.GetDB(debug)), opts),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Printf("Listening for Competitive Companion on http://%s\n", addr)
+	if err := server.ListenAndServe(); err != nil {
+		fmt.Println("Listener stopped:", err)
+	}
+}
+
+// flagOr 返回参数值，未设置时返回配置中的默认值
+func flagOr(cmd *cobra.Command, name, def string) string {
+	if v := cmd.Flag(name).Value.String(); v != "" {
+		return v
+	}
+	return def
+}
+
+func newListenHandler(svc *service.Service, opts service.CaptureOptions) http.Handler {
+	// 比赛解析时插件会连续推送多道题，逐个保存避免题目 ID 冲突
+	var mu sync.Mutex
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		// 插件的 JSON 含有 interactive、batch 等其他字段，不做严格校验
+		var in service.CaptureInput
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<20)).Decode(&in); err != nil {
+			writeError(w, &service.ValidationError{Field: "request body", Message: err.Error()})
+			return
+		}
+
+		mu.Lock()
+		problem, err := svc.CaptureProblem(r.Context(), &in, opts)
+		mu.Unlock()
+		switch {
+		case errors.Is(err, service.ErrConflict):
+			fmt.Println("Skipped existing problem:", in.URL)
+		case err != nil:
+			fmt.Println("Failed to capture problem:", err)
+		default:
+			fmt.Printf("Captured [%s] %s (%d tests) -> %s\n", problem.Slug, problem.Title, len(problem.Tests), problem.CodePath)
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, toProblemView(problem, false))
+	})
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"algo/internal/db"
+	"algo/internal/review"
+	"algo/internal/service"
+	"bufio"
+	"fmt"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review [slug]",
+	Short: "[ 间隔重复复习到期的题目 ] Review due problems with spaced repetition",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   reviewProblems,
+}
+
+func InitReviewCmd() *cobra.Command {
+	reviewCmd.Flags().BoolP("debug", "D", false, "Debug mode")
+	reviewCmd.Flags().BoolP("list", "l", false, "[ 只列出到期的题目 ] Only list due problems")
+	reviewCmd.Flags().IntP("limit", "n", 0, "[ 最多复习的题目数，0 为不限 ] Maximum number of problems, 0 for all")
+	reviewCmd.Flags().BoolP("quiz", "q", false, "[ 先显示题面，按回车后依次显示笔记与代码 ] Show the statement first, reveal the note and the code on Enter")
+	reviewCmd.Flags().IntP("grade", "g", -1, "[ 直接为指定题目评分 0~5 ] Grade the given problem directly (0-5)")
+	reviewCmd.Long = `Go through the problems that are due and grade how well you recalled each one,
+then schedule the next review with the SM-2 algorithm. A problem becomes due one
+day after it is solved; grades below 3 restart its schedule.
+With --quiz, only the description and tags are shown at first; press Enter to
+reveal the note, Enter again to reveal the code, then grade yourself.
+Grades:
+  0 complete blackout          3 recalled with serious difficulty
+  1 wrong, but looked familiar 4 recalled after some hesitation
+  2 wrong, but easy to recall  5 perfect recall
+Example:
+  algo review
+  algo review --list
+  algo review --quiz
+  algo review 0001_two-sum --grade 4`
+	return reviewCmd
+}
+
+func reviewProblems(cmd *cobra.Command, args []string) {
+	debug, _ := cmd.Flags().GetBool("debug")
+	svc := service.New(db.GetDB(debug))
+	if len(args) == 1 {
+		grade, _ := cmd.Flags().GetInt("grade")
+		if grade < 0 {
+			fmt.Println("Failed to review problem: specify the grade with --grade")
+			return
+		}
+		gradeProblem(cmd, svc, args[0], grade)
+		return
+	}
+
+	limit, _ := cmd.Flags().GetInt("limit")
+	due, err := svc.DueReviews(cmd.Context(), time.Now(), limit)
+	if err != nil {
+		fmt.Println("Failed to list due problems:", err)
+		return
+	}
+	if len(due) == 0 {
+		fmt.Println("No problems due for review")
+		return
+	}
+	fmt.Println("Due problems:", len(due))
+	if list, _ := cmd.Flags().GetBool("list"); list {
+		for i, d := range due {
+			printDue(i+1, d)
+		}
+		return
+	}
+
+	quiz, _ := cmd.Flags().GetBool("quiz")
+	reader := bufio.NewReader(cmd.InOrStdin())
+	for i, d := range due {
+		fmt.Println()
+		printDue(i+1, d)
+		if quiz && !quizProblem(reader, d) {
+			return
+		}
+		grade, ok := promptGrade(reader)
+		if !ok {
+			return
+		}
+		if grade < 0 {
+			continue
+		}
+		gradeProblem(cmd, svc, d.Problem.Slug, grade)
+	}
+}
+
+// printDue 输出到期题目及其复习状态
+func printDue(index int, d *service.DueReview) {
+	p := d.Problem
+	fmt.Printf("[%d] [%s] %s | Difficulty: %s | Tags: %s\n", index, p.Slug, p.Title, p.Difficulty, getTagNames(p.Tags))
+	if d.Review == nil {
+		fmt.Printf("Due: %s | Never reviewed\n", d.DueAt.Local().Format("2006-01-02"))
+	} else {
+		fmt.Printf("Due: %s | Interval: %d day(s) | Ease: %.2f | Last reviewed: %s\n",
+			d.DueAt.Local().Format("2006-01-02"), d.Review.Interval, d.Review.Ease, d.Review.ReviewedAt.Local().Format("2006-01-02"))
+	}
+	fmt.Println("Solution URL:", p.SolutionURL)
+}
+
+// quizProblem 显示题面，按回车后依次显示笔记与代码，输入 q 或输入结束时返回 false
+func quizProblem(reader *bufio.Reader, d *service.DueReview) bool {
+	p := d.Problem
+	fmt.Println()
+	fmt.Println(orPlaceholder(p.Description, "(no description)"))
+	if !waitEnter(reader, "note") {
+		return false
+	}
+	fmt.Println(orPlaceholder(p.Note, "(no note)"))
+	if !waitEnter(reader, "code") {
+		return false
+	}
+	code := ""
+	if p.CodePath != "" {
+		data, err := os.ReadFile(p.CodePath)
+		if err != nil {
+			fmt.Println("Failed to read code:", err)
+		}
+		code = string(data)
+	}
+	fmt.Println(orPlaceholder(code, "(no code)"))
+	return true
+}
+
+// waitEnter 等待用户按回车以显示 what
+func waitEnter(reader *bufio.Reader, what string) bool {
+	fmt.Printf("-- Press Enter to reveal the %s (q to quit) --", what)
+	line, err := reader.ReadString('\n')
+	fmt.Println()
+	return strings.TrimSpace(line) != "q" && err == nil
+}
+
+func orPlaceholder(text, placeholder string) string {
+	if strings.TrimSpace(text) == "" {
+		return placeholder
+	}
+	return strings.TrimRight(text, "\n")
+}
+
+// promptGrade 读取 0~5 的评分，s 跳过时返回 -1，q 或输入结束时 ok 为 false
+func promptGrade(reader *bufio.Reader) (grade int, ok bool) {
+	for {
+		fmt.Printf("Grade 0-%d (s to skip, q to quit): ", review.MaxGrade)
+		line, err := reader.ReadString('\n')
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "q" || (line == "" && err == io.EOF):
+			return 0, false
+		case line == "s":
+			return -1, true
+		}
+		if grade, convErr := strconv.Atoi(line); convErr == nil && grade >= 0 && grade <= review.MaxGrade {
+			return grade, true
+		}
+		if err != nil {
+			return 0, false
+		}
+		fmt.Println("Invalid grade:", line)
+	}
+}
+
+func gradeProblem(cmd *cobra.Command, svc *service.Service, slug string, grade int) {
+	r, err := svc.ReviewProblem(cmd.Context(), slug, grade, time.Now())
+	if err != nil {
+		fmt.Println("Failed to review problem:", err)
+		return
+	}
+	fmt.Printf("Next review of %s in %d day(s) on %s\n", slug, r.Interval, r.DueAt.Local().Format("2006-01-02"))
+}
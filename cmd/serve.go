@@ -0,0 +1,559 @@
+package cmd
+
+import (
+	"algo/internal/db"
+	"algo/internal/model"
+	"algo/internal/runner"
+	"algo/internal/service"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"io/fs"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed web
+var webFS embed.FS
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Args:  cobra.NoArgs,
+	Short: "[ 启动本地 Web UI 与 REST API ] Start the local web UI and REST API",
+	Run:   serve,
+}
+
+func InitServeCmd() *cobra.Command {
+	serveCmd.Flags().BoolP("debug", "D", false, "Debug mode")
+	serveCmd.Flags().StringP("addr", "a", "127.0.0.1:8080", "[ 监听地址 ] Listen address")
+	serveCmd.Long = `Serve a browser UI and a JSON REST API over the problem database.
+Only requests addressed to a loopback host from the server's own origin are
+accepted, and request bodies must be application/json. Code is accepted inline
+only, the API never reads files by path.
+Example:
+  algo serve --addr 127.0.0.1:8080
+
+API:
+  GET    /api/problems?title=&difficulty=&tags=&score=&limit=&offset=
+  POST   /api/problems
+  GET    /api/problems/{slug}
+  PUT    /api/problems/{slug}
+  DELETE /api/problems/{slug}
+  GET    /api/tags              POST /api/tags
+  PUT    /api/tags/{id}         DELETE /api/tags/{id}
+  GET    /api/contests          POST /api/contests
+  GET    /api/contests/{id}     PUT /api/contests/{id}     DELETE /api/contests/{id}
+  GET    /api/contest-types
+  GET    /api/stats
+  GET    /api/stats/timeline?granularity=day|week|month&since=&until=&timezone=`
+	return serveCmd
+}
+
+func serve(cmd *cobra.Command, args []string) {
+	debug, _ := cmd.Flags().GetBool("debug")
+	addr := cmd.Flag("addr").Value.String()
+
+	handler, err := newServeHandler(service.New(db.GetDB(debug)))
+	if err != nil {
+		fmt.Println("Failed to start server:", err)
+		return
+	}
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Printf("Serving on http://%s\n", addr)
+	if err = server.ListenAndServe(); err != nil {
+		fmt.Println("Server stopped:", err)
+	}
+}
+
+// apiServer REST API，与命令行共用 service
+type apiServer struct {
+	svc *service.Service
+}
+
+func newServeHandler(svc *service.Service) (http.Handler, error) {
+	s := &apiServer{svc: svc}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/problems", s.listProblems)
+	mux.HandleFunc("POST /api/problems", s.createProblem)
+	mux.HandleFunc("GET /api/problems/{slug}", s.getProblem)
+	mux.HandleFunc("PUT /api/problems/{slug}", s.updateProblem)
+	mux.HandleFunc("DELETE /api/problems/{slug}", s.deleteProblem)
+
+	mux.HandleFunc("GET /api/tags", s.listTags)
+	mux.HandleFunc("POST /api/tags", s.createTag)
+	mux.HandleFunc("PUT /api/tags/{id}", s.updateTag)
+	mux.HandleFunc("DELETE /api/tags/{id}", s.deleteTag)
+
+	mux.HandleFunc("GET /api/contests", s.listContests)
+	mux.HandleFunc("POST /api/contests", s.createContest)
+	mux.HandleFunc("GET /api/contests/{id}", s.getContest)
+	mux.HandleFunc("PUT /api/contests/{id}", s.updateContest)
+	mux.HandleFunc("DELETE /api/contests/{id}", s.deleteContest)
+	mux.HandleFunc("GET /api/contest-types", s.listContestTypes)
+
+	mux.HandleFunc("GET /api/stats", s.stats)
+	mux.HandleFunc("GET /api/stats/timeline", s.timeline)
+
+	web, err := fs.Sub(webFS, "web")
+	if err != nil {
+		return nil, err
+	}
+	mux.Handle("GET /", http.FileServer(http.FS(web)))
+	return localOnly(mux), nil
+}
+
+// localOnly 只接受发往回环地址、来自本站页面的请求，防止其他网页跨站请求或 DNS 重绑定读取题库
+func localOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isLoopbackHost(r.Host) {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": "host " + r.Host + " is not allowed"})
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" && origin != "http://"+r.Host {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": "origin " + origin + " is not allowed"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isLoopbackHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// problemView 接口返回的题目
+type problemView struct {
+	ID          int64     `json:"id"`
+	Slug        string    `json:"slug"`
+	Title       string    `json:"title"`
+	Difficulty  string    `json:"difficulty"`
+	Status      string    `json:"status"`
+	Tags        []string  `json:"tags"`
+	SolutionURL string    `json:"solution"`
+	Note        string    `json:"note"`
+	Description string    `json:"description"`
+	Score       *uint8    `json:"score"`
+	Rating      *int      `json:"rating,omitempty"`
+	TimeLimit   int       `json:"timeLimit,omitempty"`   // 毫秒
+	MemoryLimit int       `json:"memoryLimit,omitempty"` // MB
+	Checker     string    `json:"checker,omitempty"`
+	Epsilon     float64   `json:"epsilon,omitempty"`
+	CodePath    string    `json:"codePath"`
+	Language    string    `json:"language"`
+	Code        *string   `json:"code,omitempty"`
+	ContestID   int64     `json:"contestId,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func toProblemView(p *model.Problem, withCode bool) *problemView {
+	view := &problemView{
+		ID:          p.ID,
+		Slug:        p.Slug,
+		Title:       p.Title,
+		Difficulty:  p.Difficulty.String(),
+		Status:      string(p.Status),
+		Tags:        make([]string, 0, len(p.Tags)),
+		SolutionURL: p.SolutionURL,
+		Note:        p.Note,
+		Description: p.Description,
+		Score:       p.Score,
+		Rating:      p.Rating,
+		TimeLimit:   p.TimeLimit,
+		MemoryLimit: p.MemoryLimit,
+		Checker:     string(p.Checker),
+		Epsilon:     p.Epsilon,
+		CodePath:    p.CodePath,
+		Language:    runner.LanguageName(p.CodePath),
+		ContestID:   p.ContestID,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+	for _, t := range p.Tags {
+		view.Tags = append(view.Tags, t.Name)
+	}
+	if withCode && p.CodePath != "" {
+		if data, err := os.ReadFile(p.CodePath); err == nil {
+			code := string(data)
+			view.Code = &code
+		}
+	}
+	return view
+}
+
+func (s *apiServer) listProblems(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := &service.ListFilter{
+		Title:      q.Get("title"),
+		Difficulty: q.Get("difficulty"),
+		Tags:       service.SplitTags(q.Get("tags")),
+		Status:     q.Get("status"),
+		Limit:      100,
+	}
+	var err error
+	if filter.Score, err = service.ParseScore(q.Get("score")); err != nil {
+		writeError(w, err)
+		return
+	}
+	if filter.Limit, err = queryInt(q.Get("limit"), "limit", filter.Limit); err != nil {
+		writeError(w, err)
+		return
+	}
+	if filter.Offset, err = queryInt(q.Get("offset"), "offset", 0); err != nil {
+		writeError(w, err)
+		return
+	}
+	problems, err := s.svc.ListProblems(r.Context(), filter)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	views := make([]*problemView, 0, len(problems))
+	for _, p := range problems {
+		views = append(views, toProblemView(p, false))
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+func (s *apiServer) getProblem(w http.ResponseWriter, r *http.Request) {
+	problem, err := s.svc.GetProblem(r.Context(), r.PathValue("slug"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toProblemView(problem, true))
+}
+
+// problemRequest 新增或修改题目的请求体，与 service.AddInput 相同但不含本地文件路径，代码只能以内容提交
+type problemRequest struct {
+	Title       string              `json:"title"`
+	Difficulty  string              `json:"difficulty"`
+	Tags        []string            `json:"tags"`
+	SolutionURL string              `json:"solution"`
+	Note        string              `json:"note"`
+	Description string              `json:"description"`
+	Code        string              `json:"code"`
+	Language    string              `json:"language"`
+	Score       *int                `json:"score"`
+	Rating      *int                `json:"rating"`
+	Contest     string              `json:"contest"`
+	ContestType string              `json:"contestType"`
+	TimeLimit   int                 `json:"timeLimit"`
+	MemoryLimit int                 `json:"memoryLimit"`
+	Tests       []service.TestInput `json:"tests"`
+	Checker     string              `json:"checker"`
+	Epsilon     *float64            `json:"epsilon"`
+	Status      string              `json:"status"`
+}
+
+func (p *problemRequest) input() *service.AddInput {
+	return &service.AddInput{
+		Title:       p.Title,
+		Difficulty:  p.Difficulty,
+		Tags:        p.Tags,
+		SolutionURL: p.SolutionURL,
+		Note:        p.Note,
+		Description: p.Description,
+		Code:        p.Code,
+		Language:    p.Language,
+		Score:       p.Score,
+		Rating:      p.Rating,
+		Contest:     p.Contest,
+		ContestType: p.ContestType,
+		TimeLimit:   p.TimeLimit,
+		MemoryLimit: p.MemoryLimit,
+		Tests:       p.Tests,
+		Checker:     p.Checker,
+		Epsilon:     p.Epsilon,
+		Status:      p.Status,
+	}
+}
+
+func (s *apiServer) createProblem(w http.ResponseWriter, r *http.Request) {
+	var req problemRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	problem, err := s.svc.AddProblem(r.Context(), req.input())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, toProblemView(problem, true))
+}
+
+func (s *apiServer) updateProblem(w http.ResponseWriter, r *http.Request) {
+	var req problemRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	problem, err := s.svc.EditProblem(r.Context(), r.PathValue("slug"), (*service.EditInput)(req.input()))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toProblemView(problem, true))
+}
+
+func (s *apiServer) deleteProblem(w http.ResponseWriter, r *http.Request) {
+	if err := s.svc.RemoveProblem(r.Context(), r.PathValue("slug")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// nameRequest 新增或重命名标签的请求体
+type nameRequest struct {
+	Name string `json:"name"`
+}
+
+func (s *apiServer) listTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := s.svc.ListTags(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tags)
+}
+
+func (s *apiServer) createTag(w http.ResponseWriter, r *http.Request) {
+	var req nameRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	tag, err := s.svc.CreateTag(r.Context(), req.Name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, &service.TagCount{ID: tag.ID, Name: tag.Name})
+}
+
+func (s *apiServer) updateTag(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var req nameRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	tag, err := s.svc.RenameTag(r.Context(), id, req.Name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &service.TagCount{ID: tag.ID, Name: tag.Name})
+}
+
+func (s *apiServer) deleteTag(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	if err := s.svc.DeleteTag(r.Context(), id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// contestView 接口返回的竞赛
+type contestView struct {
+	ID       int64          `json:"id"`
+	Title    string         `json:"title"`
+	Type     string         `json:"type"`
+	Problems []*problemView `json:"problems,omitempty"`
+}
+
+// contestRequest 新增或修改竞赛的请求体
+type contestRequest struct {
+	Title string `json:"title"`
+	Type  string `json:"type"`
+}
+
+func toContestView(c *model.Contest) *contestView {
+	return &contestView{ID: c.ID, Title: c.Title, Type: c.Type.String()}
+}
+
+func (s *apiServer) listContestTypes(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, service.ContestTypes())
+}
+
+func (s *apiServer) listContests(w http.ResponseWriter, r *http.Request) {
+	contests, err := s.svc.ListContests(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	views := make([]*contestView, 0, len(contests))
+	for _, c := range contests {
+		views = append(views, toContestView(c))
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+func (s *apiServer) getContest(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	contest, err := s.svc.GetContest(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	view := toContestView(contest)
+	view.Problems = make([]*problemView, 0, len(contest.Problems))
+	for _, p := range contest.Problems {
+		view.Problems = append(view.Problems, toProblemView(p, false))
+	}
+	writeJSON(w, http.StatusOK, view)
+}
+
+func (s *apiServer) createContest(w http.ResponseWriter, r *http.Request) {
+	var req contestRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	contest, err := s.svc.CreateContest(r.Context(), req.Title, req.Type)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, toContestView(contest))
+}
+
+func (s *apiServer) updateContest(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var req contestRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	contest, err := s.svc.UpdateContest(r.Context(), id, req.Title, req.Type)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toContestView(contest))
+}
+
+func (s *apiServer) deleteContest(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	if err := s.svc.DeleteContest(r.Context(), id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *apiServer) stats(w http.ResponseWriter, r *http.Request) {
+	result, err := s.svc.Stats(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *apiServer) timeline(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := &service.TimelineQuery{
+		Granularity: q.Get("granularity"),
+		Timezone:    q.Get("timezone"),
+		Since:       q.Get("since"),
+		Until:       q.Get("until"),
+	}
+	if query.Granularity == "" {
+		query.Granularity = "day"
+	}
+	result, err := s.svc.Timeline(r.Context(), query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func queryInt(value, field string, def int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, &service.ValidationError{Field: field, Message: "must be a number"}
+	}
+	return v, nil
+}
+
+func pathID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, &service.ValidationError{Field: "id", Message: "must be a number"})
+		return 0, false
+	}
+	return id, true
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	// 只接受 JSON，跨站表单只能发送 text/plain 等简单类型
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		writeJSON(w, http.StatusUnsupportedMediaType, map[string]string{"error": "content type must be application/json"})
+		return false
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		writeError(w, &service.ValidationError{Field: "request body", Message: err.Error()})
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError 根据错误类型返回对应的状态码
+func writeError(w http.ResponseWriter, err error) {
+	var validation *service.ValidationError
+	status := http.StatusInternalServerError
+	switch {
+	case errors.As(err, &validation):
+		status = http.StatusBadRequest
+	case errors.Is(err, service.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrConflict):
+		status = http.StatusConflict
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"algo/internal/db"
+	"algo/internal/generator"
+	"algo/internal/model"
+	"algo/internal/service"
+	"algo/internal/site"
+	"algo/internal/stat"
+	"algo/pkg/config"
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"time"
+)
+
+var siteCmd = &cobra.Command{
+	Use:   "site",
+	Short: "[ 静态站点 ] Static HTML site",
+}
+
+var siteBuildCmd = &cobra.Command{
+	Use:   "build",
+	Args:  cobra.NoArgs,
+	Short: "[ 生成静态 HTML 站点 ] Build a static HTML site of all problems",
+	Run:   buildSite,
+}
+
+func InitSiteCmd() *cobra.Command {
+	siteBuildCmd.Flags().BoolP("debug", "D", false, "Debug mode")
+	siteBuildCmd.Flags().StringP("dir", "o", "", "[ 输出目录，默认为 site_dir ] Output directory, defaults to site_dir")
+	siteBuildCmd.Flags().StringP("title", "t", "Algo Notes", "[ 站点标题 ] Site title")
+	siteBuildCmd.Long = `Build problem pages, tag and contest indexes, a stats dashboard and a search index.
+The site uses relative links only and can be opened from the file system.
+Example:
+  algo site build
+  algo site build --dir ./public --title "My Notes"`
+
+	siteCmd.AddCommand(siteBuildCmd)
+	return siteCmd
+}
+
+func buildSite(cmd *cobra.Command, args []string) {
+	debug, _ := cmd.Flags().GetBool("debug")
+	dir := cmd.Flag("dir").Value.String()
+	if dir == "" {
+		dir = config.GetConfig().Dir.SiteDir
+	}
+
+	s, err := collectSite(cmd.Context(), service.New(db.GetDB(debug)))
+	if err != nil {
+		fmt.Println("Failed to load site data:", err)
+		return
+	}
+	s.Dir = dir
+	s.Title = cmd.Flag("title").Value.String()
+
+	pages, err := s.Build()
+	if err != nil {
+		fmt.Println("Failed to build site:", err)
+		return
+	}
+	fmt.Printf("Site built successfully: %d pages in %s\n", pages, dir)
+}
+
+// collectSite 读取全部题目、标签、竞赛与统计数据，题目数据与 markdown 生成共用同一套映射
+func collectSite(ctx context.Context, svc *service.Service) (*site.Site, error) {
+	problems, err := svc.PublishedProblems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	contests, err := svc.ListContests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	contestByID := make(map[int64]*model.Contest, len(contests))
+	for _, c := range contests {
+		contestByID[c.ID] = c
+	}
+
+	s := &site.Site{}
+	tagGroups := make(map[string]*site.Group)
+	contestGroups := make(map[int64]*site.Group)
+	for _, p := range problems {
+		gp, err := service.ProblemWithCode(p)
+		if err != nil {
+			// 代码文件缺失时仍然生成页面，只是不展示代码
+			fmt.Printf("Failed to read code of %s: %v\n", p.Slug, err)
+			gp = service.ProblemData(p)
+		}
+		page := (&site.Problem{Problem: gp, ID: p.ID, Link: site.ProblemLink(p.Slug)}).Highlighted()
+		for _, t := range p.Tags {
+			ref := &site.Ref{Name: t.Name, Link: site.TagLink(t.Name)}
+			page.Tags = append(page.Tags, ref)
+			group, ok := tagGroups[t.Name]
+			if !ok {
+				group = &site.Group{Name: t.Name, Link: ref.Link}
+				tagGroups[t.Name] = group
+				s.Tags = append(s.Tags, group)
+			}
+			group.Problems = append(group.Problems, page)
+		}
+		if c, ok := contestByID[p.ContestID]; ok {
+			page.Contest = &site.Ref{Name: c.Title, Link: site.ContestLink(c.ID)}
+			group, ok := contestGroups[c.ID]
+			if !ok {
+				group = &site.Group{Name: c.Title, Type: c.Type.String(), Link: page.Contest.Link}
+				contestGroups[c.ID] = group
+			}
+			group.Problems = append(group.Problems, page)
+		}
+		s.Problems = append(s.Problems, page)
+	}
+	for _, c := range contests {
+		if group, ok := contestGroups[c.ID]; ok {
+			s.Contests = append(s.Contests, group)
+		}
+	}
+
+	stats, err := collectSiteStats(ctx, svc)
+	if err != nil {
+		return nil, err
+	}
+	s.Stats = stats
+	return s, nil
+}
+
+func collectSiteStats(ctx context.Context, svc *service.Service) (*site.Stats, error) {
+	result, err := svc.Stats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cnf := config.GetConfig()
+	loc, err := cnf.Stat.Location()
+	if err != nil {
+		return nil, err
+	}
+	solvedAt, err := svc.SolvedAt(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	streak := stat.Streaks(solvedAt, loc, now)
+	heatmap := &generator.Heatmap{
+		Counts:   stat.DailyCounts(solvedAt, loc),
+		End:      now,
+		Colors:   cnf.Heatmap.Colors,
+		Location: loc,
+	}
+
+	toCounts := func(rows []service.StatCount) []*site.Count {
+		var peak int64
+		for _, r := range rows {
+			peak = max(peak, r.Count)
+		}
+		counts := make([]*site.Count, 0, len(rows))
+		for _, r := range rows {
+			c := &site.Count{Name: r.Name, Count: r.Count}
+			if peak > 0 {
+				c.Percent = float64(r.Count) * 100 / float64(peak)
+			}
+			counts = append(counts, c)
+		}
+		return counts
+	}
+	return &site.Stats{
+		Total:         result.Total,
+		Contests:      result.Contests,
+		AverageScore:  result.AverageScore,
+		Difficulties:  toCounts(result.Difficulties),
+		Tags:          toCounts(result.Tags),
+		Languages:     toCounts(result.Languages),
+		CurrentStreak: streak.Current,
+		LongestStreak: streak.Longest,
+		HeatmapSVG:    heatmap.RenderSVG(),
+		GeneratedAt:   now.In(loc).Format("2006-01-02 15:04:05"),
+	}, nil
+}
@@ -1 +1,225 @@
 package cmd
+
+import (
+	"algo/internal/db"
+	"algo/internal/service"
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+var statCmd = &cobra.Command{
+	Use:   "stat",
+	Short: "[ 统计信息 ] Show statistics of problems",
+	Run:   showStat,
+}
+
+func InitStatCmd() *cobra.Command {
+	statCmd.Long = `Show problems per status, and solved problems (solved and needs-review)
+grouped by difficulty, tag, contest type and language.
+With --timeline, show solved problems per day/week/month and daily streaks,
+counting each problem on the day it was first solved (the day it was added for
+problems solved before this was recorded).
+With --reviews, show reviews and the share recalled (grade >= 3) per week,
+or per --timeline granularity.
+Example:
+  algo stat
+  algo stat --json
+  algo stat --timeline week --since 2025-01-01 --until 2025-03-31
+  algo stat --reviews --timeline month`
+
+	statCmd.Flags().BoolP("debug", "D", false, "Debug mode")
+	statCmd.Flags().BoolP("json", "j", false, "[ 以 JSON 格式输出 ] Output as JSON")
+	statCmd.Flags().StringP("timeline", "T", "", "[ 按时间统计，粒度 ] Timeline granularity (day|week|month)")
+	statCmd.Flags().String("since", "", "[ 起始日期 ] Start date, inclusive (2006-01-02)")
+	statCmd.Flags().String("until", "", "[ 截止日期 ] End date, inclusive (2006-01-02)")
+	statCmd.Flags().Bool("reviews", false, "[ 统计复习次数与记忆保持率 ] Show review retention")
+	statCmd.Flags().String("timezone", "", "[ 时区，默认读取配置 ] Timezone, defaults to stat.timezone in algo.toml")
+	return statCmd
+}
+
+func showStat(cmd *cobra.Command, args []string) {
+	debug, _ := cmd.Flags().GetBool("debug")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	svc := service.New(db.GetDB(debug))
+	if reviews, _ := cmd.Flags().GetBool("reviews"); reviews {
+		showRetention(cmd, svc, asJSON)
+		return
+	}
+	if timeline := cmd.Flag("timeline").Value.String(); timeline != "" {
+		showTimeline(cmd, svc, timeline, asJSON)
+		return
+	}
+
+	result, err := svc.Stats(cmd.Context())
+	if err != nil {
+		fmt.Println("Failed to collect statistics:", err)
+		return
+	}
+
+	if asJSON {
+		printJSON(result)
+		return
+	}
+	printStat(result)
+}
+
+func printJSON(v any) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		fmt.Println("Failed to encode statistics:", err)
+	}
+}
+
+func printStat(result *service.Stat) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "Total problems:\t%d\n", result.Total)
+	_, _ = fmt.Fprintf(w, "Solved problems:\t%d\n", result.Solved)
+	_, _ = fmt.Fprintf(w, "Total contests:\t%d\n", result.Contests)
+	if result.AverageScore != nil {
+		_, _ = fmt.Fprintf(w, "Average score:\t%.2f\n", *result.AverageScore)
+	} else {
+		_, _ = fmt.Fprintf(w, "Average score:\t-\n")
+	}
+
+	printStatSection(w, "Status", result.Statuses, result.Total)
+	// 以下分布只统计已解决的题目
+	printStatSection(w, "Difficulty", result.Difficulties, result.Solved)
+	printStatSection(w, "Tag", result.Tags, result.Solved)
+	printStatSection(w, "Contest", result.ContestTypes, result.Solved)
+	printStatSection(w, "Language", result.Languages, result.Solved)
+
+	_, _ = fmt.Fprintf(w, "\nTotal attempts:\t%d\n", result.Attempts)
+	if result.AvgAttempts != nil {
+		_, _ = fmt.Fprintf(w, "Average attempts:\t%.2f (%d problems)\n", *result.AvgAttempts, result.Attempted)
+	} else {
+		_, _ = fmt.Fprintf(w, "Average attempts:\t-\n")
+	}
+	printStatSection(w, "Verdict", result.Verdicts, result.Attempts)
+	if len(result.MostAttempts) > 0 {
+		_, _ = fmt.Fprintf(w, "\nMost attempts\tCount\t\n")
+		_, _ = fmt.Fprintf(w, "-------------\t-----\t\n")
+		for _, r := range result.MostAttempts {
+			_, _ = fmt.Fprintf(w, "%s\t%d\t\n", r.Name, r.Count)
+		}
+	}
+	_ = w.Flush()
+}
+
+func printStatSection(w *tabwriter.Writer, title string, rows []service.StatCount, total int64) {
+	_, _ = fmt.Fprintf(w, "\n%s\tCount\tRatio\n", title)
+	_, _ = fmt.Fprintf(w, "%s\t-----\t-----\n", strings.Repeat("-", len(title)))
+	if len(rows) == 0 {
+		_, _ = fmt.Fprintf(w, "(none)\t0\t-\n")
+		return
+	}
+	for _, r := range rows {
+		ratio := "-"
+		if total > 0 {
+			ratio = fmt.Sprintf("%.1f%%", float64(r.Count)*100/float64(total))
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\n", r.Name, r.Count, ratio)
+	}
+}
+
+func showTimeline(cmd *cobra.Command, svc *service.Service, timeline string, asJSON bool) {
+	result, err := svc.Timeline(cmd.Context(), &service.TimelineQuery{
+		Granularity: timeline,
+		Timezone:    cmd.Flag("timezone").Value.String(),
+		Since:       cmd.Flag("since").Value.String(),
+		Until:       cmd.Flag("until").Value.String(),
+	})
+	if err != nil {
+		fmt.Println("Failed to collect timeline:", err)
+		return
+	}
+
+	if asJSON {
+		printJSON(result)
+		return
+	}
+	printTimeline(result)
+}
+
+func printTimeline(result *service.Timeline) {
+	var peak int
+	for _, b := range result.Buckets {
+		peak = max(peak, b.Count)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "Solved problems:\t%d\n", result.Total)
+	_, _ = fmt.Fprintf(w, "Timezone:\t%s\n", result.Timezone)
+	_, _ = fmt.Fprintf(w, "Current streak:\t%d day(s)\n", result.Streak.Current)
+	if result.Streak.Longest > 0 {
+		_, _ = fmt.Fprintf(w, "Longest streak:\t%d day(s) (%s ~ %s)\n",
+			result.Streak.Longest, result.Streak.LongestStart, result.Streak.LongestEnd)
+	} else {
+		_, _ = fmt.Fprintf(w, "Longest streak:\t0 day(s)\n")
+	}
+
+	title := strings.ToUpper(string(result.Granularity[:1])) + string(result.Granularity[1:])
+	_, _ = fmt.Fprintf(w, "\n%s\tCount\t\n", title)
+	_, _ = fmt.Fprintf(w, "%s\t-----\t\n", strings.Repeat("-", len(title)))
+	for _, b := range result.Buckets {
+		bar := ""
+		if peak > 0 {
+			bar = strings.Repeat("█", (b.Count*30+peak-1)/peak)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\n", b.Key, b.Count, bar)
+	}
+	_ = w.Flush()
+}
+
+func showRetention(cmd *cobra.Command, svc *service.Service, asJSON bool) {
+	granularity := cmd.Flag("timeline").Value.String()
+	if granularity == "" {
+		granularity = "week"
+	}
+	result, err := svc.Retention(cmd.Context(), &service.TimelineQuery{
+		Granularity: granularity,
+		Timezone:    cmd.Flag("timezone").Value.String(),
+		Since:       cmd.Flag("since").Value.String(),
+		Until:       cmd.Flag("until").Value.String(),
+	})
+	if err != nil {
+		fmt.Println("Failed to collect retention:", err)
+		return
+	}
+
+	if asJSON {
+		printJSON(result)
+		return
+	}
+	printRetention(result)
+}
+
+func printRetention(result *service.Retention) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "Total reviews:\t%d\n", result.Reviews)
+	if result.Reviews > 0 {
+		_, _ = fmt.Fprintf(w, "Retention:\t%.1f%%\n", float64(result.Recalled)*100/float64(result.Reviews))
+	} else {
+		_, _ = fmt.Fprintf(w, "Retention:\t-\n")
+	}
+	_, _ = fmt.Fprintf(w, "Due now:\t%d\n", result.Due)
+	_, _ = fmt.Fprintf(w, "Timezone:\t%s\n", result.Timezone)
+
+	title := strings.ToUpper(string(result.Granularity[:1])) + string(result.Granularity[1:])
+	_, _ = fmt.Fprintf(w, "\n%s\tReviews\tRetention\t\n", title)
+	_, _ = fmt.Fprintf(w, "%s\t-------\t---------\t\n", strings.Repeat("-", len(title)))
+	for _, b := range result.Buckets {
+		if b.Reviews == 0 {
+			_, _ = fmt.Fprintf(w, "%s\t0\t-\t\n", b.Key)
+			continue
+		}
+		bar := strings.Repeat("█", int(b.Rate*20+0.5))
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%.1f%%\t%s\n", b.Key, b.Reviews, b.Rate*100, bar)
+	}
+	_ = w.Flush()
+}
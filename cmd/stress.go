@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"algo/internal/db"
+	"algo/internal/model"
+	"algo/internal/runner"
+	"algo/internal/service"
+	"fmt"
+	"github.com/spf13/cobra"
+	"strconv"
+	"time"
+)
+
+var stressCmd = &cobra.Command{
+	Use:   "stress [slug]",
+	Short: "[ 对拍：用随机数据比较暴力解与正解 ] Stress test the solution against a brute force",
+	Args:  cobra.ExactArgs(1),
+	Run:   stressProblem,
+}
+
+func InitStressCmd() *cobra.Command {
+	stressCmd.Flags().BoolP("debug", "D", false, "Debug mode")
+	stressCmd.Flags().StringP("gen", "g", "", "[ 数据生成器，保存到 CodeDir，之后可省略 ] Generator, saved to CodeDir and reused later")
+	stressCmd.Flags().StringP("brute", "b", "", "[ 暴力解法，保存到 CodeDir，之后可省略 ] Brute force solution, saved to CodeDir and reused later")
+	stressCmd.Flags().IntP("count", "n", 1000, "[ 最多运行的轮数 ] Maximum number of iterations")
+	stressCmd.Flags().Int64P("seed", "s", 1, "[ 起始随机种子 ] First random seed")
+	stressCmd.Flags().DurationP("time-limit", "t", 0, "[ 正解的时间限制，默认同 algo test ] Time limit of the solution, defaults as in algo test")
+	stressCmd.Flags().IntP("memory-limit", "m", 0, "[ 正解的内存限制(MB)，默认同 algo test ] Memory limit of the solution in MB, defaults as in algo test")
+	stressCmd.Flags().Duration("aux-time-limit", 10*time.Second, "[ 生成器与暴力解的时间限制 ] Time limit of the generator and the brute force")
+	stressCmd.Long = `Run the generator with seeds seed, seed+1, ... (passed as its first argument),
+feed its output to the brute force and the solution, and stop on the first
+difference. The failing input is saved as a new test case of the problem.
+Example:
+  algo stress 0001_two-sum --gen gen.py --brute brute.cpp
+  algo stress 0001_two-sum -n 5000`
+	return stressCmd
+}
+
+func stressProblem(cmd *cobra.Command, args []string) {
+	debug, _ := cmd.Flags().GetBool("debug")
+	svc := service.New(db.GetDB(debug))
+	problem, err := svc.GetProblem(cmd.Context(), args[0])
+	if err != nil {
+		fmt.Println("Failed to stress test:", err)
+		return
+	}
+	genPath, err := auxFile(cmd, problem, "gen", model.AuxGenerator)
+	if err != nil {
+		fmt.Println("Failed to stress test:", err)
+		return
+	}
+	brutePath, err := auxFile(cmd, problem, "brute", model.AuxBrute)
+	if err != nil {
+		fmt.Println("Failed to stress test:", err)
+		return
+	}
+
+	var programs []*runner.Program
+	defer func() {
+		for _, p := range programs {
+			p.Close()
+		}
+	}()
+	compile := func(path string) (*runner.Program, runner.Language, bool) {
+		program, lang, err := compileCode(cmd, path)
+		if err != nil {
+			fmt.Println("Failed to stress test:", err)
+			return nil, lang, false
+		}
+		programs = append(programs, program)
+		return program, lang, true
+	}
+	gen, _, ok := compile(genPath)
+	if !ok {
+		return
+	}
+	brute, _, ok := compile(brutePath)
+	if !ok {
+		return
+	}
+	solution, lang, ok := compile(problem.CodePath)
+	if !ok {
+		return
+	}
+	checker, closeChecker, err := problemChecker(cmd, problem)
+	if err != nil {
+		fmt.Println("Failed to stress test:", err)
+		return
+	}
+	defer closeChecker()
+
+	limits := runLimits(cmd, problem, lang)
+	auxTime, _ := cmd.Flags().GetDuration("aux-time-limit")
+	auxLimits := runner.Limits{Time: auxTime}
+	count, _ := cmd.Flags().GetInt("count")
+	seed, _ := cmd.Flags().GetInt64("seed")
+	for i := 0; i < count; i, seed = i+1, seed+1 {
+		fmt.Printf("\rseed %d", seed)
+		input := gen.Run(cmd.Context(), "", auxLimits, strconv.FormatInt(seed, 10))
+		if input.Verdict != runner.AC {
+			fmt.Printf("\nGenerator failed: %s %v\n%s", input.Verdict, input.Err, input.Stderr)
+			return
+		}
+		expected := brute.Run(cmd.Context(), input.Output, auxLimits)
+		if expected.Verdict != runner.AC {
+			fmt.Printf("\nBrute force failed: %s %v\n%s", expected.Verdict, expected.Err, expected.Stderr)
+			printIndented("input", input.Output)
+			return
+		}
+		result := solution.Test(cmd.Context(), input.Output, expected.Output, limits, checker)
+		if result.Verdict == runner.AC {
+			continue
+		}
+
+		fmt.Printf("\n%s on seed %d\n", result.Verdict, seed)
+		printResult(input.Output, expected.Output, result)
+		if result.Verdict == runner.FAIL {
+			return
+		}
+		saved, err := svc.AddTests(cmd.Context(), problem.Slug, []service.TestInput{{Input: input.Output, Output: expected.Output}})
+		if err != nil {
+			fmt.Println("Failed to save test case:", err)
+			return
+		}
+		fmt.Printf("Saved as test case %d of %s\n", saved.Tests[0].ID, problem.Slug)
+		return
+	}
+	fmt.Printf("\nAll %d iterations passed\n", count)
+}
+
+// auxFile 返回辅助代码路径，指定了参数时先复制到 CodeDir
+func auxFile(cmd *cobra.Command, problem *model.Problem, flag, kind string) (string, error) {
+	if src := cmd.Flag(flag).Value.String(); src != "" {
+		return problem.SaveAux(kind, src)
+	}
+	if path := problem.AuxPath(kind); path != "" {
+		return path, nil
+	}
+	return "", fmt.Errorf("no %s file, specify one with --%s", kind, flag)
+}
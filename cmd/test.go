@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"algo/internal/db"
+	"algo/internal/model"
+	"algo/internal/runner"
+	"algo/internal/service"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 题目未记录时间与内存限制时使用
+const (
+	defaultTimeLimit   = 2 * time.Second
+	defaultMemoryLimit = 256 // MB
+)
+
+var testCmd = &cobra.Command{
+	Use:   "test [slug]",
+	Short: "[ 使用测试用例评测本地代码 ] Run the local solution against stored test cases",
+	Args:  cobra.ExactArgs(1),
+	Run:   testProblem,
+}
+
+func InitTestCmd() *cobra.Command {
+	testCmd.Flags().BoolP("debug", "D", false, "Debug mode")
+	testCmd.Flags().DurationP("time-limit", "t", 0, "[ 每个用例的 CPU 时间限制，默认使用题目的限制或 2s 并乘以语言倍数 ] CPU time limit per case, defaults to the problem limit or 2s times the language multiplier")
+	testCmd.Flags().IntP("memory-limit", "m", 0, "[ 内存限制(MB)，默认使用题目的限制或 256 ] Memory limit in MB, defaults to the problem limit or 256")
+	testCmd.Flags().Int("history", 0, "[ 只显示最近 N 次运行记录 ] Only show the last N recorded runs")
+	testCmd.Flags().StringP("interactor", "i", "", "[ 交互题的交互器，保存到 CodeDir，之后可省略 ] Interactor of an interactive problem, saved to CodeDir and reused later")
+	testCmd.Flags().Bool("transcript", false, "[ 输出每个用例的交互记录 ] Print the interaction transcript of every case")
+	testCmd.Long = `Compile the code at the problem's code path and run it against every stored
+test case, reporting AC/WA/TLE/MLE/RE with a diff for wrong answers.
+On Linux each run is limited by CPU time and memory, and the whole process group
+is killed on timeout. Time and peak memory of every run are recorded.
+With an interactor (testlib style, called with <input> <output> <answer>), the
+solution and the interactor talk through pipes and the interactor's exit code
+decides the verdict: 0 is AC, 1 or 2 is WA.
+Example:
+  algo test 0001_two-sum
+  algo test 0001_two-sum -t 500ms -m 64
+  algo test 0001_two-sum --history 20
+  algo test 1900_guess --interactor interactor.cpp`
+	return testCmd
+}
+
+func testProblem(cmd *cobra.Command, args []string) {
+	debug, _ := cmd.Flags().GetBool("debug")
+	svc := service.New(db.GetDB(debug))
+	if n, _ := cmd.Flags().GetInt("history"); n > 0 {
+		printRuns(cmd, svc, args[0], n)
+		return
+	}
+	problem, err := svc.ProblemTests(cmd.Context(), args[0])
+	if err != nil {
+		fmt.Println("Failed to test problem:", err)
+		return
+	}
+	if len(problem.Tests) == 0 {
+		fmt.Println("No test cases for", problem.Slug)
+		return
+	}
+	program, lang, err := compileCode(cmd, problem.CodePath)
+	if err != nil {
+		fmt.Println("Failed to test problem:", err)
+		return
+	}
+	defer program.Close()
+	interactor, err := problemInteractor(cmd, problem)
+	if err != nil {
+		fmt.Println("Failed to test problem:", err)
+		return
+	}
+	var checker runner.Checker
+	if interactor != nil {
+		defer interactor.Close()
+	} else {
+		var closeChecker func()
+		if checker, closeChecker, err = problemChecker(cmd, problem); err != nil {
+			fmt.Println("Failed to test problem:", err)
+			return
+		}
+		defer closeChecker()
+	}
+	limits := runLimits(cmd, problem, lang)
+	showTranscript, _ := cmd.Flags().GetBool("transcript")
+
+	passed := 0
+	runs := make([]*model.Run, 0, len(problem.Tests))
+	for i, tc := range problem.Tests {
+		var result *runner.Result
+		if interactor != nil {
+			result = program.Interact(cmd.Context(), interactor, tc.Input, tc.Output, limits, runner.Limits{Time: checkerTimeLimit})
+		} else {
+			result = program.Test(cmd.Context(), tc.Input, tc.Output, limits, checker)
+		}
+		run := newRun(problem, tc.ID, result, limits)
+		runs = append(runs, run)
+		fmt.Printf("#%d %-4s %s\n", i+1, result.Verdict, formatUsage(run))
+		if result.Verdict == runner.AC {
+			passed++
+		}
+		switch {
+		case interactor != nil && (showTranscript || result.Verdict != runner.AC):
+			printInteraction(tc.Input, result)
+		case interactor == nil && result.Verdict != runner.AC:
+			printResult(tc.Input, tc.Output, result)
+		}
+	}
+	fmt.Printf("Passed %d/%d\n", passed, len(problem.Tests))
+	if err = svc.SaveRuns(cmd.Context(), runs); err != nil {
+		fmt.Println("Failed to save runs:", err)
+	}
+}
+
+// printRuns 输出最近的运行记录
+func printRuns(cmd *cobra.Command, svc *service.Service, slug string, n int) {
+	runs, err := svc.ListRuns(cmd.Context(), slug, n)
+	if err != nil {
+		fmt.Println("Failed to list runs:", err)
+		return
+	}
+	for _, run := range runs {
+		fmt.Printf("%s  case %-4d %-4s %s\n", run.CreatedAt.Local().Format("2006-01-02 15:04:05"), run.TestCaseID, run.Verdict, formatUsage(run))
+	}
+}
+
+func newRun(problem *model.Problem, testCaseID int64, result *runner.Result, limits runner.Limits) *model.Run {
+	return &model.Run{
+		ProblemID:   problem.ID,
+		TestCaseID:  testCaseID,
+		Verdict:     string(result.Verdict),
+		TimeMs:      result.Time.Milliseconds(),
+		CPUMs:       result.CPUTime.Milliseconds(),
+		MemoryKB:    result.Memory >> 10,
+		TimeLimit:   limits.Time.Milliseconds(),
+		MemoryLimit: limits.Memory >> 20,
+	}
+}
+
+// formatUsage 输出资源占用及其占限制的比例
+func formatUsage(run *model.Run) string {
+	usage := fmt.Sprintf("%5dms  cpu %5dms", run.TimeMs, run.CPUMs)
+	if run.TimeLimit > 0 {
+		usage += fmt.Sprintf(" (%3d%%)", run.CPUMs*100/run.TimeLimit)
+	}
+	usage += fmt.Sprintf("  mem %7.1fMB", float64(run.MemoryKB)/1024)
+	if run.MemoryLimit > 0 {
+		usage += fmt.Sprintf(" (%3d%%)", run.MemoryKB*100/(run.MemoryLimit<<10))
+	}
+	return usage
+}
+
+// compileCode 按扩展名编译代码文件，编译失败时输出 CE 与编译器信息
+func compileCode(cmd *cobra.Command, path string) (*runner.Program, runner.Language, error) {
+	// 待做的题目可能还没有代码
+	if path == "" {
+		return nil, runner.Language{}, errors.New("no code file, set one with algo edit --codePath")
+	}
+	lang, ok := runner.LookupLanguage(filepath.Ext(path))
+	if !ok {
+		return nil, lang, fmt.Errorf("unsupported language %q of %s", lang.Ext, filepath.Base(path))
+	}
+	program, err := runner.Compile(cmd.Context(), path, lang)
+	var ce *runner.CompileError
+	if errors.As(err, &ce) {
+		fmt.Println(runner.CE, filepath.Base(path))
+		fmt.Print(ce.Output)
+	}
+	return program, lang, err
+}
+
+// checkerTimeLimit 自定义检查程序的时间限制
+const checkerTimeLimit = 10 * time.Second
+
+// problemChecker 按题目设置创建答案检查器，custom 模式会编译检查程序，使用完需调用返回的 close
+func problemChecker(cmd *cobra.Command, problem *model.Problem) (runner.Checker, func(), error) {
+	switch problem.Checker {
+	case model.CheckerTokens:
+		return runner.TokenChecker{}, func() {}, nil
+	case model.CheckerFloat:
+		return runner.FloatChecker{Epsilon: problem.Epsilon}, func() {}, nil
+	case model.CheckerCustom:
+		path := problem.AuxPath(model.AuxChecker)
+		if path == "" {
+			return nil, nil, errors.New("no checker file, set one with algo edit --checker-file")
+		}
+		program, _, err := compileCode(cmd, path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &runner.ProgramChecker{Program: program, Limits: runner.Limits{Time: checkerTimeLimit}}, func() { program.Close() }, nil
+	default:
+		return runner.ExactChecker{}, func() {}, nil
+	}
+}
+
+// printResult 输出未通过用例的输入、差异、检查程序说明或错误信息
+func printResult(input, expected string, result *runner.Result) {
+	printIndented("input", input)
+	switch result.Verdict {
+	case runner.WA:
+		if result.Message != "" {
+			printIndented("checker", result.Message)
+		}
+		printIndented("diff", runner.Diff(expected, result.Output))
+	case runner.RE, runner.FAIL:
+		printIndented("error", fmt.Sprint(result.Err)+"\n"+result.Stderr)
+	}
+}
+
+// problemInteractor 编译题目的交互器，指定了 --interactor 时先保存到 CodeDir，题目不是交互题时返回 nil
+func problemInteractor(cmd *cobra.Command, problem *model.Problem) (*runner.Program, error) {
+	path := problem.AuxPath(model.AuxInteractor)
+	if src := cmd.Flag("interactor").Value.String(); src != "" {
+		var err error
+		if path, err = problem.SaveAux(model.AuxInteractor, src); err != nil {
+			return nil, err
+		}
+	}
+	if path == "" {
+		return nil, nil
+	}
+	program, _, err := compileCode(cmd, path)
+	return program, err
+}
+
+// printInteraction 输出交互题用例的输入、交互器说明、错误信息与交互记录
+func printInteraction(input string, result *runner.Result) {
+	printIndented("input", input)
+	if result.Message != "" {
+		printIndented("interactor", result.Message)
+	}
+	if result.Verdict == runner.RE || result.Verdict == runner.FAIL {
+		printIndented("error", fmt.Sprint(result.Err)+"\n"+result.Stderr)
+	}
+	printIndented("transcript", result.Output)
+}
+
+// runLimits 返回每个用例的时间与内存限制，命令行参数优先，否则使用题目的限制，时间按语言倍数放宽
+func runLimits(cmd *cobra.Command, problem *model.Problem, lang runner.Language) runner.Limits {
+	limits := runner.Limits{Time: defaultTimeLimit, Memory: defaultMemoryLimit << 20}
+	if problem.TimeLimit > 0 {
+		limits.Time = time.Duration(problem.TimeLimit) * time.Millisecond
+	}
+	limits.Time = lang.Limit(limits.Time)
+	if limit, _ := cmd.Flags().GetDuration("time-limit"); limit > 0 {
+		limits.Time = limit
+	}
+	if problem.MemoryLimit > 0 {
+		limits.Memory = int64(problem.MemoryLimit) << 20
+	}
+	if mb, _ := cmd.Flags().GetInt("memory-limit"); mb > 0 {
+		limits.Memory = int64(mb) << 20
+	}
+	return limits
+}
+
+// printIndented 缩进输出多行文本，过长时截断
+func printIndented(title, text string) {
+	const maxLines = 20
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	fmt.Printf("  %s:\n", title)
+	for i, line := range lines {
+		if i == maxLines {
+			fmt.Println("    ...")
+			break
+		}
+		fmt.Println("    " + line)
+	}
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"algo/internal/db"
+	"algo/internal/model"
+	"algo/internal/service"
+	"algo/internal/testpack"
+	"fmt"
+	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var testsCmd = &cobra.Command{
+	Use:   "tests",
+	Short: "[ 导入导出测试用例 ] Import and export test cases",
+}
+
+var testsImportCmd = &cobra.Command{
+	Use:   "import [slug] [path]",
+	Short: "[ 从 oj 目录或 Polygon 打包导入测试用例 ] Import test cases from an oj directory or a Polygon package",
+	Args:  cobra.RangeArgs(1, 2),
+	Run:   importTests,
+}
+
+var testsExportCmd = &cobra.Command{
+	Use:   "export [slug] [path]",
+	Short: "[ 导出测试用例为 oj 目录或 Polygon 打包 ] Export test cases as an oj directory or a Polygon package",
+	Args:  cobra.RangeArgs(1, 2),
+	Run:   exportTests,
+}
+
+func InitTestsCmd() *cobra.Command {
+	formatHelp := "[ 格式，默认 .zip 为 polygon，其余为 oj ] Format (" + strings.Join(testpack.Formats, "|") + "), defaults to polygon for .zip and oj otherwise"
+	testsImportCmd.Flags().BoolP("debug", "D", false, "Debug mode")
+	testsImportCmd.Flags().StringP("format", "f", "", formatHelp)
+	testsImportCmd.Flags().Bool("replace", false, "[ 导入前删除已有的测试用例 ] Remove existing test cases first")
+	testsImportCmd.Long = `Import test cases into a problem. The path defaults to the oj directory "test",
+or <slug>.zip with --format polygon. In the oj layout every <name>.in with a
+matching <name>.out is a case and names starting with "sample" are samples.
+A Polygon full package (zip) also sets the time and memory limits, the checker,
+the interactor and, if empty, the description. With --replace the existing
+cases are removed in the same transaction, so a failed import keeps them.
+Example:
+  algo tests import 0001_two-sum
+  algo tests import 0001_two-sum -f polygon
+  algo tests import 0001_two-sum two-sum-7$linux.zip --replace`
+
+	testsExportCmd.Flags().BoolP("debug", "D", false, "Debug mode")
+	testsExportCmd.Flags().StringP("format", "f", "", formatHelp)
+	testsExportCmd.Long = `Export the test cases of a problem. The path defaults to the oj directory "test",
+or <slug>.zip with --format polygon. Samples are written as sample-N and other
+cases as test-N in the oj layout.
+Example:
+  algo tests export 0001_two-sum
+  algo tests export 0001_two-sum -f polygon`
+
+	testsCmd.AddCommand(testsImportCmd)
+	testsCmd.AddCommand(testsExportCmd)
+	return testsCmd
+}
+
+func importTests(cmd *cobra.Command, args []string) {
+	debug, _ := cmd.Flags().GetBool("debug")
+	replace, _ := cmd.Flags().GetBool("replace")
+	path, format := packagePath(cmd, args, args[0]+".zip")
+	pkg, err := testpack.Read(format, path)
+	if err != nil {
+		fmt.Println("Failed to read tests:", err)
+		return
+	}
+	if len(pkg.Cases) == 0 {
+		fmt.Println("No test cases found in", path)
+		return
+	}
+
+	svc := service.New(db.GetDB(debug))
+	problem, err := svc.GetProblem(cmd.Context(), args[0])
+	if err != nil {
+		fmt.Println("Failed to import tests:", err)
+		return
+	}
+	tmp, err := os.MkdirTemp("", "algo-tests-")
+	if err != nil {
+		fmt.Println("Failed to import tests:", err)
+		return
+	}
+	defer os.RemoveAll(tmp)
+
+	in := &service.ImportInput{
+		EditInput: service.EditInput{
+			TimeLimit:   pkg.TimeLimit,
+			MemoryLimit: pkg.MemoryLimit,
+			Checker:     string(pkg.Checker),
+		},
+		Replace: replace,
+	}
+	if problem.Description == "" {
+		in.Description = pkg.Description
+	}
+	if pkg.Checker == model.CheckerFloat {
+		in.Epsilon = &pkg.Epsilon
+	}
+	if pkg.CheckerSrc != nil {
+		if in.CheckerPath, err = writeSource(tmp, pkg.CheckerSrc); err != nil {
+			fmt.Println("Failed to import tests:", err)
+			return
+		}
+	}
+	if pkg.Interactor != nil {
+		if in.Interactor, err = writeSource(tmp, pkg.Interactor); err != nil {
+			fmt.Println("Failed to import tests:", err)
+			return
+		}
+	}
+	samples := 0
+	for _, c := range pkg.Cases {
+		in.Tests = append(in.Tests, service.TestInput{Input: c.Input, Output: c.Output, Sample: c.Sample})
+		if c.Sample {
+			samples++
+		}
+	}
+
+	_, removed, err := svc.ImportTests(cmd.Context(), problem.Slug, in)
+	if err != nil {
+		fmt.Println("Failed to import tests:", err)
+		return
+	}
+	if replace {
+		fmt.Printf("Removed %d test cases\n", removed)
+	}
+	fmt.Printf("Imported %d test cases (%d samples) into %s\n", len(pkg.Cases), samples, problem.Slug)
+	if pkg.Skipped > 0 {
+		fmt.Printf("Skipped %d test cases without an answer file\n", pkg.Skipped)
+	}
+}
+
+func exportTests(cmd *cobra.Command, args []string) {
+	debug, _ := cmd.Flags().GetBool("debug")
+	problem, err := service.New(db.GetDB(debug)).ProblemTests(cmd.Context(), args[0])
+	if err != nil {
+		fmt.Println("Failed to export tests:", err)
+		return
+	}
+	if len(problem.Tests) == 0 {
+		fmt.Println("No test cases for", problem.Slug)
+		return
+	}
+	path, format := packagePath(cmd, args, problem.Slug+".zip")
+	pkg, err := testpack.FromProblem(problem)
+	if err == nil {
+		err = testpack.Write(format, path, pkg)
+	}
+	if err != nil {
+		fmt.Println("Failed to export tests:", err)
+		return
+	}
+	fmt.Printf("Exported %d test cases of %s to %s\n", len(pkg.Cases), problem.Slug, path)
+}
+
+// packagePath 返回测试数据包的路径与格式，未指定路径时 oj 使用 test 目录，polygon 使用 zipName
+func packagePath(cmd *cobra.Command, args []string, zipName string) (string, string) {
+	format := cmd.Flag("format").Value.String()
+	if len(args) > 1 {
+		if format == "" {
+			format = testpack.Detect(args[1])
+		}
+		return args[1], format
+	}
+	if format == testpack.Polygon {
+		return zipName, format
+	}
+	if format == "" {
+		format = testpack.OJ
+	}
+	return "test", format
+}
+
+// writeSource 将数据包中的源码写入临时目录，保留扩展名
+func writeSource(dir string, src *testpack.Source) (string, error) {
+	path := filepath.Join(dir, filepath.Base(src.Name))
+	return path, os.WriteFile(path, src.Data, 0644)
+}
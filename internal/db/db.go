@@ -46,10 +46,20 @@ func initDB(debug bool) {
 	sqlDB.SetConnMaxLifetime(time.Hour)
 	sqlDB.SetConnMaxIdleTime(time.Minute * 30)
 
-	err = db.AutoMigrate(&model.Problem{}, &model.Tag{}, &model.Contest{})
+	err = db.AutoMigrate(&model.Problem{}, &model.Tag{}, &model.Contest{}, &model.TestCase{}, &model.Run{}, &model.Review{}, &model.ReviewLog{}, &model.Attempt{})
 	if err != nil {
 		log.Error("failed to auto migrate", zap.Error(err))
 	}
+	if err = backfillSolvedAt(db); err != nil {
+		log.Error("failed to backfill solved_at", zap.Error(err))
+	}
+}
+
+// backfillSolvedAt 旧数据没有解决时间，已解决的题目使用创建时间
+func backfillSolvedAt(db *gorm.DB) error {
+	return db.Model(&model.Problem{}).
+		Where("solved_at IS NULL AND status IN ?", model.SolvedStatuses).
+		UpdateColumn("solved_at", gorm.Expr("created_at")).Error
 }
 
 func GetDB(debug bool) *gorm.DB {
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"algo/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBackfillSolvedAt(t *testing.T) {
+	conn, err := gorm.Open(sqlite.Open("file:"+filepath.Join(t.TempDir(), "algo.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = conn.AutoMigrate(&model.Problem{}); err != nil {
+		t.Fatal(err)
+	}
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	solved := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	problems := []*model.Problem{
+		{ID: 1, Title: "A", Slug: "0001_a", Status: model.StatusSolved, CreatedAt: created},
+		{ID: 2, Title: "B", Slug: "0002_b", Status: model.StatusNeedsReview, CreatedAt: created, SolvedAt: &solved},
+		{ID: 3, Title: "C", Slug: "0003_c", Status: model.StatusTodo, CreatedAt: created},
+	}
+	if err = conn.Create(problems).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err = backfillSolvedAt(conn); err != nil {
+		t.Fatal(err)
+	}
+	var got []*model.Problem
+	if err = conn.Order("id").Find(&got).Error; err != nil {
+		t.Fatal(err)
+	}
+	want := []*time.Time{&created, &solved, nil}
+	for i, p := range got {
+		if (p.SolvedAt == nil) != (want[i] == nil) || p.SolvedAt != nil && !p.SolvedAt.Equal(*want[i]) {
+			t.Errorf("problem %s solved at %v, want %v", p.Slug, p.SolvedAt, want[i])
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package exporter
+
+import (
+	"algo/internal/model"
+	"algo/internal/runner"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	obsidianProblemDir = "Problems"
+	obsidianTagDir     = "Tags"
+	obsidianContestDir = "Contests"
+)
+
+// Obsidian 导出为 Obsidian vault：每题一篇带 YAML frontmatter 的笔记，标签与竞赛各自一篇汇总笔记
+type Obsidian struct {
+	Dir   string // vault 目录
+	Clean bool   // 导出前清空已有的题目、标签与竞赛笔记
+}
+
+// Result 导出结果
+type Result struct {
+	Problems int
+	Tags     int
+	Contests int
+}
+
+// Export 导出全部题目，problems 需预加载 Tags
+func (o *Obsidian) Export(problems []*model.Problem, contests []*model.Contest) (*Result, error) {
+	if o.Clean {
+		for _, dir := range []string{obsidianProblemDir, obsidianTagDir, obsidianContestDir} {
+			if err := os.RemoveAll(filepath.Join(o.Dir, dir)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, dir := range []string{obsidianProblemDir, obsidianTagDir, obsidianContestDir} {
+		if err := os.MkdirAll(filepath.Join(o.Dir, dir), 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	contestByID := make(map[int64]*model.Contest, len(contests))
+	for _, c := range contests {
+		contestByID[c.ID] = c
+	}
+	problemsByTag := make(map[string][]*model.Problem)
+	problemsByContest := make(map[int64][]*model.Problem)
+
+	for _, p := range problems {
+		contest := contestByID[p.ContestID]
+		if err := o.write(filepath.Join(obsidianProblemDir, noteName(p.Slug)+".md"), problemNote(p, contest)); err != nil {
+			return nil, err
+		}
+		for _, t := range p.Tags {
+			problemsByTag[t.Name] = append(problemsByTag[t.Name], p)
+		}
+		if contest != nil {
+			problemsByContest[contest.ID] = append(problemsByContest[contest.ID], p)
+		}
+	}
+
+	tagNames := make([]string, 0, len(problemsByTag))
+	for name := range problemsByTag {
+		tagNames = append(tagNames, name)
+	}
+	sort.Strings(tagNames)
+	for _, name := range tagNames {
+		if err := o.write(filepath.Join(obsidianTagDir, noteName(name)+".md"), tagNote(name, problemsByTag[name])); err != nil {
+			return nil, err
+		}
+	}
+
+	contestCount := 0
+	for _, c := range contests {
+		if err := o.write(filepath.Join(obsidianContestDir, noteName(c.Title)+".md"), contestNote(c, problemsByContest[c.ID])); err != nil {
+			return nil, err
+		}
+		contestCount++
+	}
+	return &Result{Problems: len(problems), Tags: len(tagNames), Contests: contestCount}, nil
+}
+
+func (o *Obsidian) write(rel, content string) error {
+	return os.WriteFile(filepath.Join(o.Dir, rel), []byte(content), 0644)
+}
+
+func problemNote(p *model.Problem, contest *model.Contest) string {
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	writeYAML(&sb, "title", p.Title)
+	writeYAML(&sb, "slug", p.Slug)
+	writeYAML(&sb, "difficulty", p.Difficulty.String())
+	writeYAML(&sb, "status", string(p.Status))
+	sb.WriteString("tags:\n")
+	for _, t := range p.Tags {
+		sb.WriteString("  - " + yamlString(tagName(t.Name)) + "\n")
+	}
+	if p.Score != nil {
+		sb.WriteString(fmt.Sprintf("score: %d\n", *p.Score))
+	} else {
+		sb.WriteString("score: null\n")
+	}
+	if contest != nil {
+		writeYAML(&sb, "contest", wikilink(obsidianContestDir, contest.Title, contest.Title))
+		writeYAML(&sb, "contest_type", contest.Type.String())
+	} else {
+		sb.WriteString("contest: null\n")
+	}
+	writeYAML(&sb, "url", p.SolutionURL)
+	writeYAML(&sb, "created", p.CreatedAt.Format(time.RFC3339))
+	writeYAML(&sb, "updated", p.UpdatedAt.Format(time.RFC3339))
+	sb.WriteString("---\n\n")
+
+	sb.WriteString("# " + p.Title + "\n\n")
+	tags := make([]string, 0, len(p.Tags))
+	hubs := make([]string, 0, len(p.Tags))
+	for _, t := range p.Tags {
+		tags = append(tags, "#"+tagName(t.Name))
+		hubs = append(hubs, wikilink(obsidianTagDir, t.Name, t.Name))
+	}
+	sb.WriteString("- Difficulty: #difficulty/" + p.Difficulty.String() + "\n")
+	if len(tags) > 0 {
+		sb.WriteString("- Tags: " + strings.Join(tags, " ") + "\n")
+		sb.WriteString("- Topics: " + strings.Join(hubs, ", ") + "\n")
+	}
+	if contest != nil {
+		sb.WriteString("- Contest: " + wikilink(obsidianContestDir, contest.Title, contest.Title) + "\n")
+	}
+	if p.SolutionURL != "" {
+		sb.WriteString("- Link: [" + p.Title + "](" + p.SolutionURL + ")\n")
+	}
+
+	sb.WriteString("\n## 题目描述\n\n")
+	sb.WriteString(orDefault(p.Description, "暂无题目描述") + "\n")
+	sb.WriteString("\n## 解题思路\n\n")
+	sb.WriteString(orDefault(p.Note, "暂无解题思路") + "\n")
+	sb.WriteString("\n## 代码实现\n\n")
+	if data, err := os.ReadFile(p.CodePath); err == nil {
+		language := runner.LanguageName(p.CodePath)
+		sb.WriteString("~~~" + language + "\n" + strings.TrimRight(string(data), "\n") + "\n~~~\n")
+	} else {
+		sb.WriteString("暂无代码实现\n")
+	}
+	return sb.String()
+}
+
+func tagNote(name string, problems []*model.Problem) string {
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	writeYAML(&sb, "tag", tagName(name))
+	sb.WriteString(fmt.Sprintf("count: %d\n", len(problems)))
+	sb.WriteString("---\n\n")
+	sb.WriteString("# " + name + "\n\n")
+	sb.WriteString("#" + tagName(name) + "\n\n")
+	writeProblemList(&sb, problems)
+	return sb.String()
+}
+
+func contestNote(c *model.Contest, problems []*model.Problem) string {
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	writeYAML(&sb, "contest", c.Title)
+	writeYAML(&sb, "type", c.Type.String())
+	sb.WriteString(fmt.Sprintf("count: %d\n", len(problems)))
+	sb.WriteString("---\n\n")
+	sb.WriteString("# " + c.Title + "\n\n")
+	writeProblemList(&sb, problems)
+	return sb.String()
+}
+
+func writeProblemList(sb *strings.Builder, problems []*model.Problem) {
+	if len(problems) == 0 {
+		sb.WriteString("暂无题目\n")
+		return
+	}
+	for _, p := range problems {
+		sb.WriteString("- " + wikilink(obsidianProblemDir, p.Slug, p.Title) + " · " + p.Difficulty.String() + "\n")
+	}
+}
+
+// wikilink 生成带路径的 wikilink，避免不同目录下同名笔记冲突
+func wikilink(dir, name, alias string) string {
+	return "[[" + dir + "/" + noteName(name) + "|" + strings.NewReplacer("|", "-", "]", ")", "[", "(").Replace(alias) + "]]"
+}
+
+// noteName 去掉 Obsidian 文件名中不允许出现的字符
+func noteName(name string) string {
+	name = strings.NewReplacer(
+		"/", "-", "\\", "-", ":", "-", "*", "-", "?", "-", "\"", "-",
+		"<", "-", ">", "-", "|", "-", "#", "-", "^", "-", "[", "(", "]", ")",
+	).Replace(strings.TrimSpace(name))
+	if name == "" {
+		return "untitled"
+	}
+	return name
+}
+
+// tagName 转为 Obsidian 标签，标签中不能包含空格
+func tagName(name string) string {
+	return strings.Join(strings.Fields(name), "-")
+}
+
+func writeYAML(sb *strings.Builder, key, value string) {
+	sb.WriteString(key + ": " + yamlString(value) + "\n")
+}
+
+// yamlString JSON 字符串同时是合法的 YAML 双引号字符串
+func yamlString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+func orDefault(s, def string) string {
+	if strings.TrimSpace(s) == "" {
+		return def
+	}
+	return s
+}
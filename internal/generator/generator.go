@@ -1,12 +1,49 @@
 package generator
 
 import (
+	"github.com/flosch/pongo2"
+	"strings"
 	"sync"
 )
 
 var template string
 var once sync.Once
 
+var indexTemplate string
+var indexOnce sync.Once
+
+var contestTemplate string
+var contestOnce sync.Once
+
+var tagTemplate string
+var tagOnce sync.Once
+
+// cellEscaper 转义表格单元格中的竖线，并将换行合并为空格，避免破坏 Markdown 表格
+var cellEscaper = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ", "\r", " ")
+
+func init() {
+	// cell 过滤器：{{ a.Note|cell }}
+	pongo2.RegisterFilter("cell", func(in *pongo2.Value, _ *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+		return pongo2.AsValue(cellEscaper.Replace(in.String())), nil
+	})
+}
+
+// compiled 已编译的模板，key 为模板内容
+var compiled sync.Map
+
+// Compile 编译模板并按内容缓存，可并发调用
+func Compile(tpl string) (*pongo2.Template, error) {
+	if t, ok := compiled.Load(tpl); ok {
+		return t.(*pongo2.Template), nil
+	}
+	t, err := pongo2.FromString(tpl)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := compiled.LoadOrStore(tpl, t)
+	return actual.(*pongo2.Template), nil
+}
+
 func GetTemplate() string {
 	once.Do(func() {
 		template = getTemplate()
@@ -14,18 +51,53 @@ func GetTemplate() string {
 	return template
 }
 
+func GetIndexTemplate() string {
+	indexOnce.Do(func() {
+		indexTemplate = getIndexTemplate()
+	})
+	return indexTemplate
+}
+
+func GetContestTemplate() string {
+	contestOnce.Do(func() {
+		contestTemplate = getContestTemplate()
+	})
+	return contestTemplate
+}
+
+func GetTagTemplate() string {
+	tagOnce.Do(func() {
+		tagTemplate = getTagTemplate()
+	})
+	return tagTemplate
+}
+
 type Problem struct {
 	Title       string
 	Difficulty  string
+	Status      string
 	Tags        []string
 	SolutionURL string
 	Score       *uint8
+	Rating      *int
+	TimeLimit   int // 毫秒，0 表示未知
+	MemoryLimit int // MB，0 表示未知
 	CreatedAt   string
 	UpdatedAt   string
 	Slug        string
 	Description string
 	Solution    string
 	Code        *Code
+	Attempts    []*Attempt
+}
+
+// Attempt 一次做题尝试
+type Attempt struct {
+	CreatedAt string
+	Verdict   string
+	Duration  string
+	Language  string
+	Note      string
 }
 
 type Code struct {
@@ -33,6 +105,52 @@ type Code struct {
 	Data     string
 }
 
+// Contest 竞赛文档，Sections 为按题目模板渲染好的各题文档
+type Contest struct {
+	Title    string
+	Type     string
+	Problems []*Problem
+	Sections []string
+}
+
+// Index 汇总索引
+type Index struct {
+	Total        int
+	Difficulties []*Count
+	Problems     []*IndexProblem
+	Tags         []*Group
+	Contests     []*Group
+	Heatmap      string // 热力图相对路径，为空时不展示
+	GeneratedAt  string
+}
+
+type Count struct {
+	Name  string
+	Count int
+}
+
+// IndexProblem 索引中的一道题目，Link 为相对 MarkdownDir 的文档路径
+type IndexProblem struct {
+	ID          int64
+	Title       string
+	Slug        string
+	Difficulty  string
+	Tags        []string
+	Score       *uint8
+	SolutionURL string
+	Contest     string
+	Link        string
+	CreatedAt   string
+}
+
+// Group 按标签或竞赛分组的题目，Link 为分组自身的文档路径，可为空
+type Group struct {
+	Name     string
+	Type     string
+	Link     string
+	Problems []*IndexProblem
+}
+
 func getTemplate() string {
 	template = `
 # {{ problem.Title }}
@@ -40,10 +158,14 @@ func getTemplate() string {
 | 属性 | 内容 |
 | ---- | ---- |
 | **难度** | {{ problem.Difficulty }} |
-| **标签** | {% for t in problem.Tags %}{{ t }}{% if not forloop.Last %}, {% endif %}{% endfor %} |
+{% if problem.Status %}| **状态** | {{ problem.Status }} |
+{% endif %}| **标签** | {% for t in problem.Tags %}{{ t }}{% if not forloop.Last %}, {% endif %}{% endfor %} |
 | **链接** | [在线题目]({{ problem.SolutionURL }}) |
 {% if problem.Score != none %}| **评分** | {{ problem.Score }} |{% endif %}
-| **创建时间** | {{ problem.CreatedAt }} |
+{% if problem.Rating %}| **Rating** | {{ problem.Rating }} |
+{% endif %}{% if problem.TimeLimit %}| **时间限制** | {{ problem.TimeLimit }} ms |
+{% endif %}{% if problem.MemoryLimit %}| **内存限制** | {{ problem.MemoryLimit }} MB |
+{% endif %}| **创建时间** | {{ problem.CreatedAt }} |
 | **更新时间** | {{ problem.UpdatedAt }} |
 | **Slug** | {{ problem.Slug }} |
 
@@ -51,7 +173,7 @@ func getTemplate() string {
 
 ## 📖 题目描述
 
-{{ problem.Description|default:"暂无题目描述" }}
+{{ problem.Description|safe|default:"暂无题目描述" }}
 
 ---
 
@@ -60,10 +182,18 @@ func getTemplate() string {
 {{ problem.Solution|safe|default:"暂无解题思路" }}
 
 ---
+{% if problem.Attempts %}
+## 📝 做题记录
 
+| # | 时间 | 结果 | 耗时 | 语言 | 备注 |
+| ---- | ---- | ---- | ---- | ---- | ---- |
+{% for a in problem.Attempts %}| {{ forloop.Counter }} | {{ a.CreatedAt }} | {{ a.Verdict }} | {{ a.Duration }} | {{ a.Language }} | {{ a.Note|cell }} |
+{% endfor %}
+---
+{% endif %}
 ## 🛠 代码实现
 
-{% if problem.Code != None %}
+{% if problem.Code %}
 ~~~{{ problem.Code.Language }}
 {{ problem.Code.Data|safe }}
 ~~~
@@ -74,3 +204,64 @@ func getTemplate() string {
 `
 	return template
 }
+
+func getIndexTemplate() string {
+	indexTemplate = `# 算法题笔记索引
+
+> 共 **{{ index.Total }}** 道题目，生成于 {{ index.GeneratedAt }}
+
+{% if index.Heatmap %}![做题热力图]({{ index.Heatmap }})
+
+{% endif %}| 难度 | 数量 |
+| ---- | ---- |
+{% for d in index.Difficulties %}| {{ d.Name }} | {{ d.Count }} |
+{% endfor %}
+---
+
+## 📚 全部题目
+
+| # | 题目 | 难度 | 标签 | 评分 | 竞赛 |
+| ---- | ---- | ---- | ---- | ---- | ---- |
+{% for p in index.Problems %}| {{ p.ID }} | [{{ p.Title }}]({{ p.Link }}) | {{ p.Difficulty }} | {% for t in p.Tags %}{{ t }}{% if not forloop.Last %}, {% endif %}{% endfor %} | {% if p.Score != none %}{{ p.Score }}{% endif %} | {{ p.Contest }} |
+{% endfor %}
+---
+
+## 🏷 按标签
+
+{% for g in index.Tags %}### {{ g.Name }} ({{ g.Problems|length }})
+
+{% for p in g.Problems %}- [{{ p.Title }}]({{ p.Link }}) · {{ p.Difficulty }}
+{% endfor %}
+{% empty %}暂无标签
+{% endfor %}
+---
+
+## 🏆 按竞赛
+
+{% for g in index.Contests %}### {% if g.Link %}[{{ g.Name }}]({{ g.Link }}){% else %}{{ g.Name }}{% endif %} · {{ g.Type }}
+
+{% for p in g.Problems %}- [{{ p.Title }}]({{ p.Link }}) · {{ p.Difficulty }}
+{% endfor %}
+{% empty %}暂无竞赛
+{% endfor %}`
+	return indexTemplate
+}
+
+func getContestTemplate() string {
+	contestTemplate = `{% for s in contest.Sections %}{{ s|safe }}
+---
+{% endfor %}`
+	return contestTemplate
+}
+
+func getTagTemplate() string {
+	tagTemplate = `# 🏷 {{ tag.Name }}
+
+> 共 **{{ tag.Problems|length }}** 道题目
+
+| # | 题目 | 难度 | 评分 | 竞赛 |
+| ---- | ---- | ---- | ---- | ---- |
+{% for p in tag.Problems %}| {{ p.ID }} | [{{ p.Title }}](../{{ p.Link }}) | {{ p.Difficulty }} | {% if p.Score != none %}{{ p.Score }}{% endif %} | {{ p.Contest }} |
+{% endfor %}`
+	return tagTemplate
+}
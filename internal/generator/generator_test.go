@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"algo/internal/importer"
+	"github.com/flosch/pongo2"
+	"strings"
+	"testing"
+)
+
+func TestAttemptNoteCell(t *testing.T) {
+	tpl, err := Compile(GetTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	problem := &Problem{
+		Title:    "A",
+		Attempts: []*Attempt{{CreatedAt: "2024-01-02 03:04:05", Verdict: "WA", Duration: "5m", Language: "cpp", Note: "a|b\nc"}},
+	}
+	out, err := tpl.Execute(pongo2.Context{"problem": problem})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `| 1 | 2024-01-02 03:04:05 | WA | 5m | cpp | a\|b c |`
+	if !strings.Contains(out, want+"\n") {
+		t.Errorf("attempt row not found, want %q in:\n%s", want, out)
+	}
+}
+
+func TestImportedDescription(t *testing.T) {
+	tpl, err := Compile(GetTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	description := importer.HTMLToMarkdown("<p>1 &lt;= n &lt; 10<sup>5</sup>, a &amp; b, &lt;tag&gt;</p>")
+	out, err := tpl.Execute(pongo2.Context{"problem": &Problem{Title: "A", Description: description}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 题面已经是 markdown，不能再次转义
+	want := `1 <= n < 10<sup>5</sup>, a & b, \<tag>`
+	if !strings.Contains(out, want+"\n") {
+		t.Errorf("description not rendered as is, want %q in:\n%s", want, out)
+	}
+}
+
+func TestRatingRow(t *testing.T) {
+	tpl, err := Compile(GetTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rating := 1600
+	for _, tt := range []struct {
+		rating *int
+		want   bool
+	}{{nil, false}, {&rating, true}} {
+		out, err := tpl.Execute(pongo2.Context{"problem": &Problem{Title: "A", Rating: tt.rating}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(out, "| **Rating** |"); got != tt.want {
+			t.Errorf("rating %v: row shown = %v, want %v", tt.rating, got, tt.want)
+		}
+		if tt.want && !strings.Contains(out, "| **Rating** | 1600 |\n") {
+			t.Errorf("rating row missing its value:\n%s", out)
+		}
+	}
+}
+
+func TestAttributeTableContiguous(t *testing.T) {
+	tpl, err := Compile(GetTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rating := 1600
+	for _, p := range []*Problem{
+		{Title: "A", Slug: "0001_a"},
+		{Title: "A", Slug: "0001_a", Status: "solved", TimeLimit: 1000},
+		{Title: "A", Slug: "0001_a", Status: "solved", MemoryLimit: 256, Rating: &rating},
+	} {
+		out, err := tpl.Execute(pongo2.Context{"problem": p})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 属性表中间不能有空行，否则后面的行不再属于表格
+		start := strings.Index(out, "| 属性 |")
+		end := strings.Index(out, "| **Slug** |")
+		if start < 0 || end < 0 || strings.Contains(out[start:end], "\n\n") {
+			t.Errorf("attribute table is broken for %+v:\n%s", p, out)
+		}
+		if got := strings.Contains(out, "| **时间限制** |"); got != (p.TimeLimit > 0) {
+			t.Errorf("time limit %d: row shown = %v", p.TimeLimit, got)
+		}
+		if got := strings.Contains(out, "| **内存限制** |"); got != (p.MemoryLimit > 0) {
+			t.Errorf("memory limit %d: row shown = %v", p.MemoryLimit, got)
+		}
+	}
+}
+
+func TestSolvedProblemWithoutCode(t *testing.T) {
+	tpl, err := Compile(GetTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		code *Code
+		want string
+	}{
+		{nil, "暂无代码实现"},
+		{&Code{Language: "cpp", Data: "int main() {}"}, "~~~cpp\nint main() {}\n~~~"},
+	} {
+		out, err := tpl.Execute(pongo2.Context{"problem": &Problem{Title: "A", Status: "solved", Code: tt.code}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out, tt.want) {
+			t.Errorf("code %+v: want %q in:\n%s", tt.code, tt.want, out)
+		}
+		if tt.code == nil && strings.Contains(out, "~~~") {
+			t.Errorf("problem without code rendered a code fence:\n%s", out)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+)
+
+const (
+	heatmapWeeks    = 53 // 52 周加上当前未满的一周
+	heatmapCell     = 11
+	heatmapGap      = 3
+	heatmapLeft     = 30
+	heatmapTop      = 20
+	heatmapFontSize = 9
+)
+
+// DefaultHeatmapColors 默认配色，第一个颜色表示没有做题
+var DefaultHeatmapColors = []string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"}
+
+// Heatmap 做题热力图，按天统计数量
+type Heatmap struct {
+	Counts   map[string]int // key 为 2006-01-02
+	End      time.Time      // 最后一天，所在时区即热力图时区
+	Colors   []string       // 由浅到深的颜色，至少两个
+	Title    string
+	Location *time.Location
+}
+
+// heatmapLevel 将数量映射到颜色下标，0 只表示没有做题
+func heatmapLevel(count, peak, levels int) int {
+	if count <= 0 || peak <= 0 {
+		return 0
+	}
+	level := (count*(levels-1) + peak - 1) / peak
+	return min(max(level, 1), levels-1)
+}
+
+// RenderSVG 渲染最近 52 周的 GitHub 风格热力图，输出不依赖任何外部资源
+func (h *Heatmap) RenderSVG() string {
+	colors := append([]string(nil), h.Colors...)
+	if len(colors) < 2 {
+		colors = append([]string(nil), DefaultHeatmapColors...)
+	}
+	for i, c := range colors {
+		colors[i] = html.EscapeString(c)
+	}
+	loc := h.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	end := h.End.In(loc)
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, loc)
+	// 每列是一周，从周日开始
+	start := end.AddDate(0, 0, -int(end.Weekday())-(heatmapWeeks-1)*7)
+
+	peak, total := 0, 0
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		count := h.Counts[day.Format("2006-01-02")]
+		peak = max(peak, count)
+		total += count
+	}
+
+	width := heatmapLeft + heatmapWeeks*(heatmapCell+heatmapGap) + 10
+	height := heatmapTop + 7*(heatmapCell+heatmapGap) + 30
+
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="-apple-system,BlinkMacSystemFont,Segoe UI,Helvetica,Arial,sans-serif" font-size="%d">`+"\n",
+		width, height, width, height, heatmapFontSize)
+	title := h.Title
+	if title == "" {
+		title = fmt.Sprintf("%d problems in the last year", total)
+	}
+	_, _ = fmt.Fprintf(&sb, "<title>%s</title>\n", html.EscapeString(title))
+	sb.WriteString(`<rect width="100%" height="100%" fill="#ffffff"/>` + "\n")
+
+	// 周几标签
+	for row, label := range []string{"", "Mon", "", "Wed", "", "Fri", ""} {
+		if label == "" {
+			continue
+		}
+		y := heatmapTop + row*(heatmapCell+heatmapGap) + heatmapCell - 2
+		_, _ = fmt.Fprintf(&sb, `<text x="0" y="%d" fill="#767676">%s</text>`+"\n", y, label)
+	}
+
+	lastMonth := time.Month(0)
+	for week := 0; week < heatmapWeeks; week++ {
+		x := heatmapLeft + week*(heatmapCell+heatmapGap)
+		weekStart := start.AddDate(0, 0, week*7)
+		// 月份标签放在月份变化的那一列上方，第一列离下个月太近时不显示
+		if week > 0 && weekStart.Month() != lastMonth || week == 0 && weekStart.Day() <= 14 {
+			_, _ = fmt.Fprintf(&sb, `<text x="%d" y="%d" fill="#767676">%s</text>`+"\n",
+				x, heatmapTop-6, weekStart.Format("Jan"))
+		}
+		lastMonth = weekStart.Month()
+
+		for row := 0; row < 7; row++ {
+			day := weekStart.AddDate(0, 0, row)
+			if day.After(end) {
+				break
+			}
+			key := day.Format("2006-01-02")
+			count := h.Counts[key]
+			y := heatmapTop + row*(heatmapCell+heatmapGap)
+			_, _ = fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" ry="2" fill="%s" data-date="%s" data-count="%d"><title>%d problem(s) on %s</title></rect>`+"\n",
+				x, y, heatmapCell, heatmapCell, colors[heatmapLevel(count, peak, len(colors))], key, count, count, key)
+		}
+	}
+
+	// 图例
+	legendY := heatmapTop + 7*(heatmapCell+heatmapGap) + 10
+	legendX := width - 10 - len(colors)*(heatmapCell+heatmapGap) - 60
+	_, _ = fmt.Fprintf(&sb, `<text x="%d" y="%d" fill="#767676">Less</text>`+"\n", legendX, legendY+heatmapCell-2)
+	for i, color := range colors {
+		_, _ = fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" ry="2" fill="%s"/>`+"\n",
+			legendX+30+i*(heatmapCell+heatmapGap), legendY, heatmapCell, heatmapCell, color)
+	}
+	_, _ = fmt.Fprintf(&sb, `<text x="%d" y="%d" fill="#767676">More</text>`+"\n",
+		legendX+30+len(colors)*(heatmapCell+heatmapGap)+2, legendY+heatmapCell-2)
+	_, _ = fmt.Fprintf(&sb, `<text x="%d" y="%d" fill="#767676">%s</text>`+"\n",
+		heatmapLeft, legendY+heatmapCell-2, html.EscapeString(title))
+
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// ManifestFile 记录已生成文件输入哈希的清单文件名，位于输出目录下
+const ManifestFile = ".algo-gen.json"
+
+// Manifest 已生成文件的清单，key 为相对输出目录的路径，value 为渲染输入的哈希
+type Manifest struct {
+	dir   string
+	mu    sync.Mutex
+	Files map[string]string `json:"files"`
+}
+
+// LoadManifest 读取 dir 下的清单，不存在时返回空清单
+func LoadManifest(dir string) (*Manifest, error) {
+	m := &Manifest{dir: dir, Files: make(map[string]string)}
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return m, nil
+		}
+		return nil, err
+	}
+	if err = json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+	if m.Files == nil {
+		m.Files = make(map[string]string)
+	}
+	return m, nil
+}
+
+// Hash 计算渲染输入的哈希，inputs 需可被 json 序列化
+func Hash(inputs ...any) (string, error) {
+	h := sha256.New()
+	encoder := json.NewEncoder(h)
+	for _, in := range inputs {
+		if err := encoder.Encode(in); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Fresh 判断文件是否已按相同输入生成过且仍然存在
+func (m *Manifest) Fresh(rel, hash string) bool {
+	m.mu.Lock()
+	old, ok := m.Files[rel]
+	m.mu.Unlock()
+	if !ok || old != hash {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(m.dir, rel))
+	return err == nil
+}
+
+func (m *Manifest) Set(rel, hash string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Files[rel] = hash
+}
+
+// Prune 删除清单中存在但不在 keep 中的文件，返回被删除的相对路径
+func (m *Manifest) Prune(keep map[string]bool) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	removed := make([]string, 0)
+	for rel := range m.Files {
+		if keep[rel] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(m.dir, rel)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, err
+		}
+		delete(m.Files, rel)
+		removed = append(removed, rel)
+	}
+	sort.Strings(removed)
+	return removed, nil
+}
+
+func (m *Manifest) Save() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(m.dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(m.dir, ManifestFile), data, 0644)
+}
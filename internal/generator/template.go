@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Kind 模板类型
+type Kind string
+
+const (
+	KindProblem Kind = "problem"
+	KindContest Kind = "contest"
+	KindIndex   Kind = "index"
+	KindTag     Kind = "tag"
+)
+
+// Kinds 全部模板类型
+var Kinds = []Kind{KindProblem, KindContest, KindIndex, KindTag}
+
+// Builtin 返回内置模板
+func Builtin(kind Kind) string {
+	switch kind {
+	case KindContest:
+		return GetContestTemplate()
+	case KindIndex:
+		return GetIndexTemplate()
+	case KindTag:
+		return GetTagTemplate()
+	default:
+		return GetTemplate()
+	}
+}
+
+// Loader 从模板目录查找用户模板，找不到时回退到内置模板
+type Loader struct {
+	Dir       string          // 模板目录
+	Files     map[Kind]string // 各类型模板文件，相对 Dir 或绝对路径
+	Overrides map[Kind]string // 一次性覆盖的模板文件，必须存在
+}
+
+// Path 返回模板文件路径，第二个返回值表示是否为一次性覆盖
+func (l *Loader) Path(kind Kind) (string, bool) {
+	if override := l.Overrides[kind]; override != "" {
+		return override, true
+	}
+	file := l.Files[kind]
+	if file == "" {
+		file = string(kind) + ".md"
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(l.Dir, file)
+	}
+	return file, false
+}
+
+// Load 读取模板内容，source 为模板来源，内置模板为 "builtin"
+func (l *Loader) Load(kind Kind) (tpl string, source string, err error) {
+	path, override := l.Path(kind)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !override && errors.Is(err, os.ErrNotExist) {
+			return Builtin(kind), "builtin", nil
+		}
+		return "", "", fmt.Errorf("failed to read %s template: %w", kind, err)
+	}
+	if _, err = Compile(string(data)); err != nil {
+		return "", "", fmt.Errorf("invalid %s template %s: %w", kind, path, err)
+	}
+	return string(data), path, nil
+}
@@ -0,0 +1,216 @@
+// Package codeforces 通过 Codeforces API 导入题目与所属比赛
+package codeforces
+
+import (
+	"algo/internal/importer"
+	"algo/pkg/config"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Name Codeforces 题目所属竞赛的类型
+const Name = "codeforces"
+
+func init() {
+	importer.Register(&Codeforces{})
+}
+
+// Codeforces 通过 Codeforces API 获取题目与所属比赛，BaseURL 为空时使用配置文件中的地址
+type Codeforces struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+type response struct {
+	Status  string          `json:"status"`
+	Comment string          `json:"comment"`
+	Result  json.RawMessage `json:"result"`
+}
+
+type problem struct {
+	ContestID int      `json:"contestId"`
+	Index     string   `json:"index"`
+	Name      string   `json:"name"`
+	Rating    int      `json:"rating"`
+	Tags      []string `json:"tags"`
+}
+
+type standings struct {
+	Contest struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"contest"`
+	Problems []problem `json:"problems"`
+}
+
+type problemset struct {
+	Problems []problem `json:"problems"`
+}
+
+func (c *Codeforces) Name() string      { return Name }
+func (c *Codeforces) Aliases() []string { return []string{"c", "cf"} }
+
+func (c *Codeforces) Match(rawURL string) bool {
+	_, _, _, ok := c.parse(rawURL)
+	return ok
+}
+
+// parse 解析 /contest/<id>/、/contest/<id>/problem/<index>、/problemset/problem/<id>/<index>
+// 以及训练场 /gym/<id>/、/gym/<id>/problem/<index> 形式的链接，section 为 contest 或 gym
+func (c *Codeforces) parse(rawURL string) (section string, contestID int, index string, ok bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || !importer.MatchHost(u.Hostname(), "codeforces.com") {
+		return "", 0, "", false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	var id string
+	switch {
+	case len(parts) == 4 && (parts[0] == "contest" || parts[0] == "gym") && parts[2] == "problem":
+		section, id, index = parts[0], parts[1], parts[3]
+	case len(parts) == 4 && parts[0] == "problemset" && parts[1] == "problem":
+		section, id, index = "contest", parts[2], parts[3]
+	case len(parts) == 2 && (parts[0] == "contest" || parts[0] == "gym"):
+		section, id = parts[0], parts[1]
+	default:
+		return "", 0, "", false
+	}
+	contestID, err = strconv.Atoi(id)
+	if err != nil || contestID <= 0 {
+		return "", 0, "", false
+	}
+	return section, contestID, strings.ToUpper(index), true
+}
+
+// FetchProblem 获取题目名称、标签与评分，以及所属比赛名称
+func (c *Codeforces) FetchProblem(ctx context.Context, rawURL string) (*importer.Problem, error) {
+	section, contestID, index, ok := c.parse(rawURL)
+	if !ok || index == "" {
+		return nil, fmt.Errorf("%w: %s", importer.ErrUnsupportedURL, rawURL)
+	}
+	contest, err := c.fetch(ctx, rawURL, section, contestID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range contest.Problems {
+		if strings.HasSuffix(p.URL, "/problem/"+index) {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d%s", importer.ErrNotFound, contestID, index)
+}
+
+// FetchContest 获取比赛名称及全部题目
+func (c *Codeforces) FetchContest(ctx context.Context, rawURL string) (*importer.Contest, error) {
+	section, contestID, _, ok := c.parse(rawURL)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", importer.ErrUnsupportedURL, rawURL)
+	}
+	return c.fetch(ctx, rawURL, section, contestID)
+}
+
+func (c *Codeforces) fetch(ctx context.Context, rawURL, section string, contestID int) (*importer.Contest, error) {
+	var st standings
+	err := c.call(ctx, "contest.standings", url.Values{
+		"contestId": {strconv.Itoa(contestID)},
+		"from":      {"1"},
+		"count":     {"1"},
+	}, &st)
+	if err != nil {
+		return nil, err
+	}
+	// 比赛进行中或刚结束时 standings 中没有评分，从题库中补充，训练场的题目不在题库中
+	for i := range st.Problems {
+		if section == "gym" || st.Problems[i].Rating > 0 {
+			continue
+		}
+		var ps problemset
+		if err = c.call(ctx, "problemset.problems", nil, &ps); err != nil {
+			return nil, err
+		}
+		ratings := map[string]int{}
+		for _, p := range ps.Problems {
+			if p.ContestID == contestID {
+				ratings[strings.ToUpper(p.Index)] = p.Rating
+			}
+		}
+		for j := range st.Problems {
+			if st.Problems[j].Rating == 0 {
+				st.Problems[j].Rating = ratings[strings.ToUpper(st.Problems[j].Index)]
+			}
+		}
+		break
+	}
+
+	contest := &importer.Contest{
+		Title: st.Contest.Name,
+		Type:  Name,
+		URL:   importer.SiteURL(rawURL, fmt.Sprintf("/%s/%d", section, contestID)),
+	}
+	for _, p := range st.Problems {
+		index := strings.ToUpper(p.Index)
+		contest.Problems = append(contest.Problems, &importer.Problem{
+			Title:       p.Name,
+			Difficulty:  RatingDifficulty(p.Rating),
+			Tags:        p.Tags,
+			URL:         importer.SiteURL(rawURL, fmt.Sprintf("/%s/%d/problem/%s", section, contestID, index)),
+			Rating:      p.Rating,
+			Contest:     contest.Title,
+			ContestType: Name,
+		})
+	}
+	return contest, nil
+}
+
+// RatingDifficulty 按 Codeforces 评分划分难度，没有评分时返回空字符串
+func RatingDifficulty(rating int) string {
+	switch {
+	case rating <= 0:
+		return ""
+	case rating < 1400:
+		return "easy"
+	case rating < 2000:
+		return "medium"
+	default:
+		return "hard"
+	}
+}
+
+// call 调用 Codeforces API，status 不为 OK 时返回 comment 作为错误
+func (c *Codeforces) call(ctx context.Context, method string, params url.Values, result any) error {
+	base := c.BaseURL
+	if base == "" {
+		base = config.GetConfig().Codeforces.BaseURL
+	}
+	endpoint := strings.TrimRight(base, "/") + "/api/" + method
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := importer.Client(c.Client).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// 调用失败时 Codeforces 同样返回 JSON，只是状态码为 4xx
+	var data response
+	if err = json.NewDecoder(io.LimitReader(resp.Body, 64<<20)).Decode(&data); err != nil {
+		return fmt.Errorf("codeforces %s responded %s", method, resp.Status)
+	}
+	if data.Status != "OK" {
+		if strings.Contains(data.Comment, "not found") {
+			return fmt.Errorf("%w: %s", importer.ErrNotFound, data.Comment)
+		}
+		return fmt.Errorf("codeforces %s: %s", method, data.Comment)
+	}
+	return json.Unmarshal(data.Result, result)
+}
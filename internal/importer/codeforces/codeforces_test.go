@@ -0,0 +1,209 @@
+package codeforces
+
+import (
+	"algo/internal/importer"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const standingsOK = `{"status": "OK", "result": {
+	"contest": {"id": 1900, "name": "Codeforces Round 911 (Div. 2)"},
+	"problems": [
+		{"contestId": 1900, "index": "A", "name": "Cover in Water", "rating": 800, "tags": ["constructive algorithms", "greedy"]},
+		{"contestId": 1900, "index": "C", "name": "Anji's Binary Tree", "tags": ["dfs and similar", "trees"]}
+	]
+}}`
+
+const problemsetOK = `{"status": "OK", "result": {"problems": [
+	{"contestId": 1900, "index": "C", "name": "Anji's Binary Tree", "rating": 1300},
+	{"contestId": 1899, "index": "C", "name": "Other", "rating": 2400}
+]}}`
+
+// newServer 模拟 Codeforces API，calls 记录每个方法被调用的次数
+func newServer(t *testing.T, standings string) (*httptest.Server, map[string]int) {
+	t.Helper()
+	calls := map[string]int{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/contest.standings", func(w http.ResponseWriter, r *http.Request) {
+		calls["contest.standings"]++
+		if got := r.URL.Query().Get("contestId"); got != "1900" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status": "FAILED", "comment": "contestId: Contest with id ` + got + ` not found"}`))
+			return
+		}
+		w.Write([]byte(standings))
+	})
+	mux.HandleFunc("GET /api/problemset.problems", func(w http.ResponseWriter, r *http.Request) {
+		calls["problemset.problems"]++
+		w.Write([]byte(problemsetOK))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, calls
+}
+
+func TestFetchProblem(t *testing.T) {
+	srv, calls := newServer(t, standingsOK)
+	c := &Codeforces{BaseURL: srv.URL, Client: srv.Client()}
+
+	got, err := c.FetchProblem(context.Background(), "https://codeforces.com/contest/1900/problem/c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &importer.Problem{
+		Title:       "Anji's Binary Tree",
+		Difficulty:  "easy",
+		Tags:        []string{"dfs and similar", "trees"},
+		URL:         "https://codeforces.com/contest/1900/problem/C",
+		Rating:      1300,
+		Contest:     "Codeforces Round 911 (Div. 2)",
+		ContestType: Name,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FetchProblem() = %+v, want %+v", got, want)
+	}
+	// 缺少评分时只查询一次题库
+	if calls["problemset.problems"] != 1 {
+		t.Errorf("problemset.problems called %d times, want 1", calls["problemset.problems"])
+	}
+}
+
+func TestFetchProblemFromProblemset(t *testing.T) {
+	srv, _ := newServer(t, standingsOK)
+	c := &Codeforces{BaseURL: srv.URL, Client: srv.Client()}
+
+	got, err := c.FetchProblem(context.Background(), "https://codeforces.com/problemset/problem/1900/A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "Cover in Water" || got.Rating != 800 || got.URL != "https://codeforces.com/contest/1900/problem/A" {
+		t.Errorf("FetchProblem() = %+v", got)
+	}
+}
+
+func TestFetchProblemRated(t *testing.T) {
+	rated := strings.Replace(standingsOK, `"name": "Anji's Binary Tree",`, `"name": "Anji's Binary Tree", "rating": 1300,`, 1)
+	srv, calls := newServer(t, rated)
+	c := &Codeforces{BaseURL: srv.URL, Client: srv.Client()}
+
+	if _, err := c.FetchProblem(context.Background(), "https://codeforces.com/contest/1900/problem/A"); err != nil {
+		t.Fatal(err)
+	}
+	if calls["problemset.problems"] != 0 {
+		t.Errorf("problemset.problems called %d times, want 0 when standings have ratings", calls["problemset.problems"])
+	}
+}
+
+func TestFetchProblemNotFound(t *testing.T) {
+	srv, _ := newServer(t, standingsOK)
+	c := &Codeforces{BaseURL: srv.URL, Client: srv.Client()}
+
+	_, err := c.FetchProblem(context.Background(), "https://codeforces.com/contest/1900/problem/Z")
+	if !errors.Is(err, importer.ErrNotFound) {
+		t.Errorf("FetchProblem() error = %v, want ErrNotFound", err)
+	}
+	_, err = c.FetchContest(context.Background(), "https://codeforces.com/contest/1")
+	if !errors.Is(err, importer.ErrNotFound) {
+		t.Errorf("FetchContest() error = %v, want ErrNotFound", err)
+	}
+	_, err = c.FetchProblem(context.Background(), "https://codeforces.com/contest/1900")
+	if !errors.Is(err, importer.ErrUnsupportedURL) {
+		t.Errorf("FetchProblem() error = %v, want ErrUnsupportedURL", err)
+	}
+}
+
+func TestFetchContest(t *testing.T) {
+	srv, _ := newServer(t, standingsOK)
+	c := &Codeforces{BaseURL: srv.URL, Client: srv.Client()}
+
+	got, err := c.FetchContest(context.Background(), "https://codeforces.com/contest/1900/problem/A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "Codeforces Round 911 (Div. 2)" || got.Type != Name || got.URL != "https://codeforces.com/contest/1900" {
+		t.Errorf("FetchContest() = %+v", got)
+	}
+	var titles []string
+	for _, p := range got.Problems {
+		titles = append(titles, p.Title)
+	}
+	if want := []string{"Cover in Water", "Anji's Binary Tree"}; !reflect.DeepEqual(titles, want) {
+		t.Errorf("FetchContest() problems = %v, want %v", titles, want)
+	}
+}
+
+func TestAPIFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("<html>maintenance</html>"))
+	}))
+	defer srv.Close()
+	c := &Codeforces{BaseURL: srv.URL, Client: srv.Client()}
+
+	_, err := c.FetchContest(context.Background(), "https://codeforces.com/contest/1900")
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("FetchContest() error = %v, want status in error", err)
+	}
+}
+
+func TestRatingDifficulty(t *testing.T) {
+	tests := map[int]string{0: "", 800: "easy", 1399: "easy", 1400: "medium", 1999: "medium", 2000: "hard", 3500: "hard"}
+	for rating, want := range tests {
+		if got := RatingDifficulty(rating); got != want {
+			t.Errorf("RatingDifficulty(%d) = %q, want %q", rating, got, want)
+		}
+	}
+}
+
+func TestFetchGymProblem(t *testing.T) {
+	gym := `{"status": "OK", "result": {
+		"contest": {"id": 104520, "name": "2023 ICPC Training"},
+		"problems": [{"contestId": 104520, "index": "B", "name": "Gym Problem", "tags": []}]
+	}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/contest.standings" || r.URL.Query().Get("contestId") != "104520" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(gym))
+	}))
+	defer srv.Close()
+	c := &Codeforces{BaseURL: srv.URL, Client: srv.Client()}
+
+	got, err := c.FetchProblem(context.Background(), "https://codeforces.com/gym/104520/problem/B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "Gym Problem" || got.URL != "https://codeforces.com/gym/104520/problem/B" || got.Contest != "2023 ICPC Training" {
+		t.Errorf("FetchProblem() = %+v", got)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	c := &Codeforces{}
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://codeforces.com/contest/1900/problem/C", true},
+		{"https://codeforces.com/contest/1900", true},
+		{"https://codeforces.com/problemset/problem/1900/C", true},
+		{"https://codeforces.com/gym/104520/problem/B", true},
+		{"https://codeforces.com/gym/104520", true},
+		{"https://m1.codeforces.com/contest/1900/problem/C", true},
+		{"https://codeforces.example.com/contest/1900/problem/C", false},
+		{"https://notcodeforces.com/contest/1900/problem/C", false},
+		{"https://codeforces.com/blog/entry/1", false},
+		{"https://codeforces.com/contest/abc/problem/C", false},
+		{"https://codeforces.com/gym/104520/attachments", false},
+	}
+	for _, tt := range tests {
+		if got := c.Match(tt.url); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package importer
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	attrRe      = regexp.MustCompile(`([a-zA-Z-]+)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	blankLineRe = regexp.MustCompile(`\n{3,}`)
+	// 正文中的 markdown 元字符需要转义，否则 a*b、x_i 之类的题面会被渲染成格式
+	mdEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`)
+	// 只有看起来像标签开头的 < 需要转义，1 < n 这类比较保持原样
+	tagStartRe = regexp.MustCompile(`<([a-zA-Z/!?])`)
+)
+
+// HTMLToMarkdown 将题面中常见的 HTML 转换为 markdown，无法识别的标签只保留文本
+func HTMLToMarkdown(s string) string {
+	c := &mdConverter{}
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			c.text(s)
+			break
+		}
+		c.text(s[:i])
+		s = s[i:]
+		if strings.HasPrefix(s, "<!--") {
+			end := strings.Index(s, "-->")
+			if end < 0 {
+				break
+			}
+			s = s[end+3:]
+			continue
+		}
+		j := strings.IndexByte(s, '>')
+		if j < 0 {
+			c.text(s)
+			break
+		}
+		c.tag(s[1:j])
+		s = s[j+1:]
+	}
+	out := strings.TrimSpace(string(c.out))
+	return blankLineRe.ReplaceAllString(out, "\n\n")
+}
+
+// mdList 当前所在的列表，ordered 为 false 时 n 无意义
+type mdList struct {
+	ordered bool
+	n       int
+}
+
+type mdConverter struct {
+	out   []byte
+	pre   int
+	code  int // 行内代码中不转义
+	lists []*mdList
+	links []string
+}
+
+func (c *mdConverter) atLineStart() bool {
+	return len(c.out) == 0 || c.out[len(c.out)-1] == '\n'
+}
+
+func (c *mdConverter) trimTrailingSpace() {
+	for len(c.out) > 0 && c.out[len(c.out)-1] == ' ' {
+		c.out = c.out[:len(c.out)-1]
+	}
+}
+
+func (c *mdConverter) newline() {
+	c.trimTrailingSpace()
+	if !c.atLineStart() {
+		c.out = append(c.out, '\n')
+	}
+}
+
+func (c *mdConverter) blankLine() {
+	c.newline()
+	if len(c.out) > 0 && !strings.HasSuffix(string(c.out), "\n\n") {
+		c.out = append(c.out, '\n')
+	}
+}
+
+func (c *mdConverter) write(s string) {
+	c.out = append(c.out, s...)
+}
+
+func (c *mdConverter) text(s string) {
+	s = html.UnescapeString(s)
+	if c.pre > 0 {
+		if strings.HasSuffix(string(c.out), "```\n") {
+			s = strings.TrimLeft(s, "\r\n")
+		}
+		c.write(s)
+		return
+	}
+	// 合并连续空白，&nbsp; 也视为空白
+	fields := strings.Fields(s)
+	lastSpace := len(c.out) > 0 && c.out[len(c.out)-1] == ' '
+	if len(fields) == 0 {
+		if s != "" && !c.atLineStart() && !lastSpace {
+			c.write(" ")
+		}
+		return
+	}
+	text := strings.Join(fields, " ")
+	if c.code == 0 {
+		text = tagStartRe.ReplaceAllString(mdEscaper.Replace(text), `\<$1`)
+	}
+	if first, _ := utf8.DecodeRuneInString(s); unicode.IsSpace(first) && !c.atLineStart() && !lastSpace {
+		text = " " + text
+	}
+	if last, _ := utf8.DecodeLastRuneInString(s); unicode.IsSpace(last) {
+		text += " "
+	}
+	c.write(text)
+}
+
+func (c *mdConverter) tag(raw string) {
+	closing := strings.HasPrefix(raw, "/")
+	raw = strings.TrimSuffix(strings.TrimPrefix(raw, "/"), "/")
+	name, attrs, _ := strings.Cut(strings.Join(strings.Fields(raw), " "), " ")
+	name = strings.ToLower(name)
+
+	if c.pre > 0 && name != "pre" {
+		return // 代码块中忽略格式标签
+	}
+	switch name {
+	case "p", "div", "blockquote", "table", "tr":
+		c.blankLine()
+	case "br":
+		c.trimTrailingSpace()
+		c.write("  \n")
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		c.blankLine()
+		if !closing {
+			level, _ := strconv.Atoi(name[1:])
+			c.write(strings.Repeat("#", level) + " ")
+		}
+	case "strong", "b":
+		c.write("**")
+	case "em", "i":
+		c.write("*")
+	case "code":
+		if closing {
+			c.code = max(c.code-1, 0)
+		} else {
+			c.code++
+		}
+		c.write("`")
+	case "sup", "sub":
+		if closing {
+			c.write("</" + name + ">")
+		} else {
+			c.write("<" + name + ">")
+		}
+	case "pre":
+		if closing {
+			c.pre--
+			c.newline()
+			c.write("```")
+			c.blankLine()
+		} else {
+			c.blankLine()
+			c.write("```\n")
+			c.pre++
+		}
+	case "ul", "ol":
+		if closing {
+			if len(c.lists) > 0 {
+				c.lists = c.lists[:len(c.lists)-1]
+			}
+			if len(c.lists) == 0 {
+				c.blankLine()
+			}
+		} else {
+			c.newline()
+			c.lists = append(c.lists, &mdList{ordered: name == "ol"})
+		}
+	case "li":
+		if closing {
+			c.newline()
+			return
+		}
+		c.newline()
+		if len(c.lists) == 0 {
+			c.write("- ")
+			return
+		}
+		list := c.lists[len(c.lists)-1]
+		c.write(strings.Repeat("  ", len(c.lists)-1))
+		if list.ordered {
+			list.n++
+			c.write(strconv.Itoa(list.n) + ". ")
+		} else {
+			c.write("- ")
+		}
+	case "a":
+		if closing {
+			if len(c.links) == 0 {
+				return
+			}
+			href := c.links[len(c.links)-1]
+			c.links = c.links[:len(c.links)-1]
+			if href != "" {
+				c.write("](" + href + ")")
+			}
+			return
+		}
+		href := attr(attrs, "href")
+		c.links = append(c.links, href)
+		if href != "" {
+			c.write("[")
+		}
+	case "img":
+		c.write("![" + attr(attrs, "alt") + "](" + attr(attrs, "src") + ")")
+	case "td", "th":
+		if !closing {
+			c.write(" ")
+		}
+	}
+}
+
+func attr(attrs, name string) string {
+	for _, m := range attrRe.FindAllStringSubmatch(attrs, -1) {
+		if strings.EqualFold(m[1], name) {
+			return html.UnescapeString(m[2] + m[3])
+		}
+	}
+	return ""
+}
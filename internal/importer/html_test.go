@@ -0,0 +1,60 @@
+package importer
+
+import "testing"
+
+func TestHTMLToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"paragraphs", "<p>Hello</p><p>World</p>", "Hello\n\nWorld"},
+		{"whitespace", "<p>a \n\t b&nbsp;c</p>", "a b c"},
+		{"entities", "<p>1 &lt;= n &amp;&amp; n &gt; 0</p>", `1 <= n && n > 0`},
+		{"escape tag start", "<p>a &lt;b&gt; &lt;/c&gt; &lt;!x</p>", `a \<b> \</c> \<!x`},
+		{"emphasis", "<p><strong>Note</strong> and <em>this</em></p>", "**Note** and *this*"},
+		{"inline code", "<p>return <code>a[i]*b_j</code></p>", "return `a[i]*b_j`"},
+		{"escape text", "<p>a*b, x_i, [1,2] and `c`</p>", "a\\*b, x\\_i, \\[1,2\\] and \\`c\\`"},
+		{"escape backslash", `<p>C:\dir</p>`, `C:\\dir`},
+		{"pre", "<pre>\n1 *2\n_3\n</pre>", "```\n1 *2\n_3\n```"},
+		{"pre tags ignored", "<pre><strong>Input:</strong> a_1</pre>", "```\nInput: a_1\n```"},
+		{"heading", "<h2>Title</h2><p>x</p>", "## Title\n\nx"},
+		{"unordered list", "<ul><li>one</li><li>two</li></ul>", "- one\n- two"},
+		{"ordered list", "<ol><li>one</li><li>two</li></ol>", "1. one\n2. two"},
+		{"nested list", "<ul><li>a<ol><li>b</li></ol></li></ul>", "- a\n  1. b"},
+		{"link", `<a href="https://example.com/a_b">see</a>`, "[see](https://example.com/a_b)"},
+		{"link without href", "<a>plain</a>", "plain"},
+		{"image", `<img alt="graph" src="https://example.com/g.png" />`, "![graph](https://example.com/g.png)"},
+		{"sup", "<p>10<sup>9</sup></p>", "10<sup>9</sup>"},
+		{"br", "a<br/>b", "a  \nb"},
+		{"comment", "a<!-- hidden -->b", "ab"},
+		{"blank lines", "<p>a</p><p></p><div></div><p>b</p>", "a\n\nb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTMLToMarkdown(tt.in); got != tt.want {
+				t.Errorf("HTMLToMarkdown(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchHost(t *testing.T) {
+	tests := []struct {
+		host, domain string
+		want         bool
+	}{
+		{"leetcode.com", "leetcode.com", true},
+		{"www.leetcode.com", "leetcode.com", true},
+		{"LeetCode.com", "leetcode.com", true},
+		{"evilleetcode.com", "leetcode.com", false},
+		{"leetcode.com.evil.io", "leetcode.com", false},
+		{"leetcode.cn", "leetcode.com", false},
+		{"", "leetcode.com", false},
+	}
+	for _, tt := range tests {
+		if got := MatchHost(tt.host, tt.domain); got != tt.want {
+			t.Errorf("MatchHost(%q, %q) = %v, want %v", tt.host, tt.domain, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Package importer 定义在线题库的 Provider 接口与按链接查找的注册表，
+// 各题库实现位于子包中，通过空导入在 init 中注册。
+package importer
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrUnsupportedURL 无法识别的题目链接
+var ErrUnsupportedURL = errors.New("unsupported problem url")
+
+// ErrNotFound 在线题库中不存在该题目
+var ErrNotFound = errors.New("problem not found")
+
+// ErrNotContest 链接不属于任何比赛
+var ErrNotContest = errors.New("not a contest url")
+
+// Problem 从在线题库导入的题目信息
+type Problem struct {
+	Title       string
+	Difficulty  string // easy|medium|hard
+	Tags        []string
+	Description string // markdown
+	URL         string // 题目页面链接
+	Rating      int    // 在线题库的难度分，没有时为 0
+	Contest     string // 所属比赛名称，没有时为空
+	ContestType string
+}
+
+// Contest 从在线题库导入的比赛及其题目
+type Contest struct {
+	Title    string
+	Type     string // 提供方名称
+	URL      string
+	Problems []*Problem
+}
+
+var defaultClient = &http.Client{Timeout: 15 * time.Second}
+
+// Client 返回 c，为 nil 时返回带超时的默认客户端
+func Client(c *http.Client) *http.Client {
+	if c != nil {
+		return c
+	}
+	return defaultClient
+}
+
+// MatchHost 判断不含端口的 host 是否为 domain 或其子域名，忽略大小写
+func MatchHost(host, domain string) bool {
+	host, domain = strings.ToLower(host), strings.ToLower(domain)
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// SiteURL 使用原链接的协议与域名拼接 path，去掉子页面与查询参数
+func SiteURL(rawURL, path string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return rawURL
+	}
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: path}).String()
+}
@@ -0,0 +1,271 @@
+// Package leetcode 通过 GraphQL 与比赛接口导入 LeetCode 及力扣中国站的题目
+package leetcode
+
+import (
+	"algo/internal/importer"
+	"algo/pkg/config"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const questionQuery = `query questionData($titleSlug: String!) {
+  question(titleSlug: $titleSlug) {
+    questionFrontendId
+    title
+    titleSlug
+    content
+    difficulty
+    topicTags { name slug }
+  }
+}`
+
+// 力扣中国站额外返回中文标题与题面
+const questionQueryCN = `query questionData($titleSlug: String!) {
+  question(titleSlug: $titleSlug) {
+    questionFrontendId
+    title
+    titleSlug
+    content
+    translatedTitle
+    translatedContent
+    difficulty
+    topicTags { name slug translatedName }
+  }
+}`
+
+func init() {
+	importer.Register(&LeetCode{
+		Type:  "leetcode",
+		Short: []string{"l", "lc"},
+		Host:  "leetcode.com",
+		base:  func() string { return config.GetConfig().LeetCode.BaseURL },
+	})
+	importer.Register(&LeetCode{
+		Type:       "leetcode-cn",
+		Short:      []string{"lcn"},
+		Host:       "leetcode.cn",
+		Translated: true,
+		base:       func() string { return config.GetConfig().LeetCodeCN.BaseURL },
+	})
+}
+
+// LeetCode 一个 LeetCode 站点，BaseURL 为空时使用配置文件中的地址
+type LeetCode struct {
+	Type       string
+	Short      []string
+	Host       string // 链接中的域名，如 leetcode.com
+	Translated bool   // 使用中文标题与题面
+	BaseURL    string
+	Client     *http.Client
+
+	base func() string
+}
+
+type questionResponse struct {
+	Data struct {
+		Question *struct {
+			QuestionFrontendID string `json:"questionFrontendId"`
+			Title              string `json:"title"`
+			TitleSlug          string `json:"titleSlug"`
+			Content            string `json:"content"`
+			TranslatedTitle    string `json:"translatedTitle"`
+			TranslatedContent  string `json:"translatedContent"`
+			Difficulty         string `json:"difficulty"`
+			TopicTags          []struct {
+				Name           string `json:"name"`
+				Slug           string `json:"slug"`
+				TranslatedName string `json:"translatedName"`
+			} `json:"topicTags"`
+		} `json:"question"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type contestInfo struct {
+	Contest struct {
+		Title     string `json:"title"`
+		TitleSlug string `json:"title_slug"`
+	} `json:"contest"`
+	Questions []struct {
+		Title     string `json:"title"`
+		TitleSlug string `json:"title_slug"`
+	} `json:"questions"`
+}
+
+func (l *LeetCode) Name() string      { return l.Type }
+func (l *LeetCode) Aliases() []string { return l.Short }
+
+func (l *LeetCode) Match(rawURL string) bool {
+	_, _, ok := l.parse(rawURL)
+	return ok
+}
+
+// parse 解析 /problems/<slug>/、/contest/<contest>/ 与 /contest/<contest>/problems/<slug>/ 形式的链接
+func (l *LeetCode) parse(rawURL string) (contest, slug string, ok bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || !importer.MatchHost(u.Hostname(), l.Host) {
+		return "", "", false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch {
+	case len(parts) >= 2 && parts[0] == "problems" && parts[1] != "":
+		return "", parts[1], true
+	case len(parts) >= 4 && parts[0] == "contest" && parts[2] == "problems" && parts[3] != "":
+		return parts[1], parts[3], true
+	case len(parts) == 2 && parts[0] == "contest" && parts[1] != "":
+		return parts[1], "", true
+	}
+	return "", "", false
+}
+
+// FetchProblem 获取题目标题、难度、标签与题面，比赛中的题目同时返回比赛名称
+func (l *LeetCode) FetchProblem(ctx context.Context, rawURL string) (*importer.Problem, error) {
+	contest, slug, ok := l.parse(rawURL)
+	if !ok || slug == "" {
+		return nil, fmt.Errorf("%w: %s", importer.ErrUnsupportedURL, rawURL)
+	}
+	problem, err := l.question(ctx, rawURL, slug)
+	if err != nil {
+		return nil, err
+	}
+	if contest != "" {
+		info, err := l.contest(ctx, contest)
+		if err != nil {
+			return nil, err
+		}
+		problem.Contest = info.Contest.Title
+		problem.ContestType = l.Type
+	}
+	return problem, nil
+}
+
+// FetchContest 获取比赛名称及全部题目
+func (l *LeetCode) FetchContest(ctx context.Context, rawURL string) (*importer.Contest, error) {
+	contest, _, ok := l.parse(rawURL)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", importer.ErrUnsupportedURL, rawURL)
+	}
+	if contest == "" {
+		return nil, fmt.Errorf("%w: %s", importer.ErrNotContest, rawURL)
+	}
+	info, err := l.contest(ctx, contest)
+	if err != nil {
+		return nil, err
+	}
+	result := &importer.Contest{
+		Title: info.Contest.Title,
+		Type:  l.Type,
+		URL:   importer.SiteURL(rawURL, "/contest/"+contest+"/"),
+	}
+	for _, q := range info.Questions {
+		problem, err := l.question(ctx, rawURL, q.TitleSlug)
+		if err != nil {
+			return nil, err
+		}
+		problem.Contest = result.Title
+		problem.ContestType = l.Type
+		result.Problems = append(result.Problems, problem)
+	}
+	return result, nil
+}
+
+func (l *LeetCode) baseURL() string {
+	if l.BaseURL == "" && l.base != nil {
+		return strings.TrimRight(l.base(), "/")
+	}
+	return strings.TrimRight(l.BaseURL, "/")
+}
+
+// question 通过 GraphQL 接口获取单个题目
+func (l *LeetCode) question(ctx context.Context, rawURL, slug string) (*importer.Problem, error) {
+	query := questionQuery
+	if l.Translated {
+		query = questionQueryCN
+	}
+	body, err := json.Marshal(map[string]any{
+		"operationName": "questionData",
+		"query":         query,
+		"variables":     map[string]string{"titleSlug": slug},
+	})
+	if err != nil {
+		return nil, err
+	}
+	base := l.baseURL()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/graphql", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Referer", base+"/problems/"+slug+"/")
+
+	var data questionResponse
+	if err = l.do(req, &data); err != nil {
+		return nil, err
+	}
+	if len(data.Errors) > 0 {
+		return nil, fmt.Errorf("%s: %s", l.Type, data.Errors[0].Message)
+	}
+	q := data.Data.Question
+	if q == nil {
+		return nil, fmt.Errorf("%w: %s", importer.ErrNotFound, slug)
+	}
+
+	problem := &importer.Problem{
+		Title:       q.Title,
+		Difficulty:  strings.ToLower(q.Difficulty),
+		Description: importer.HTMLToMarkdown(q.Content),
+		URL:         importer.SiteURL(rawURL, "/problems/"+slug+"/"),
+	}
+	if l.Translated && q.TranslatedTitle != "" {
+		problem.Title = q.TranslatedTitle
+	}
+	if l.Translated && q.TranslatedContent != "" {
+		problem.Description = importer.HTMLToMarkdown(q.TranslatedContent)
+	}
+	for _, t := range q.TopicTags {
+		problem.Tags = append(problem.Tags, t.Name)
+	}
+	return problem, nil
+}
+
+// contest 通过比赛接口获取比赛名称与题目列表
+func (l *LeetCode) contest(ctx context.Context, slug string) (*contestInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.baseURL()+"/contest/api/info/"+slug+"/", nil)
+	if err != nil {
+		return nil, err
+	}
+	var info contestInfo
+	if err = l.do(req, &info); err != nil {
+		return nil, err
+	}
+	if info.Contest.Title == "" {
+		return nil, fmt.Errorf("%w: contest %s", importer.ErrNotFound, slug)
+	}
+	return &info, nil
+}
+
+func (l *LeetCode) do(req *http.Request, result any) error {
+	resp, err := importer.Client(l.Client).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", importer.ErrNotFound, req.URL.Path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded %s", l.Type, resp.Status)
+	}
+	if err = json.NewDecoder(io.LimitReader(resp.Body, 8<<20)).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", l.Type, err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package leetcode
+
+import (
+	"algo/internal/importer"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newServer 模拟 GraphQL 与比赛接口，questions 以 titleSlug 为键
+func newServer(t *testing.T, questions map[string]string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		var body struct {
+			Query     string            `json:"query"`
+			Variables map[string]string `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode graphql request: %v", err)
+		}
+		q, ok := questions[body.Variables["titleSlug"]]
+		if !ok {
+			q = "null"
+		}
+		w.Write([]byte(`{"data":{"question":` + q + `}}`))
+	})
+	mux.HandleFunc("GET /contest/api/info/weekly-contest-1/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"contest": {"title": "Weekly Contest 1", "title_slug": "weekly-contest-1"},
+			"questions": [{"title": "Two Sum", "title_slug": "two-sum"}]
+		}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+const twoSum = `{
+	"questionFrontendId": "1",
+	"title": "Two Sum",
+	"titleSlug": "two-sum",
+	"content": "<p>Given <code>nums</code>, return a_i.</p>",
+	"translatedTitle": "两数之和",
+	"translatedContent": "<p>给定 <code>nums</code></p>",
+	"difficulty": "Easy",
+	"topicTags": [{"name": "Array", "slug": "array"}, {"name": "Hash Table", "slug": "hash-table"}]
+}`
+
+func TestFetchProblem(t *testing.T) {
+	srv := newServer(t, map[string]string{"two-sum": twoSum})
+	l := &LeetCode{Type: "leetcode", Host: "leetcode.com", BaseURL: srv.URL, Client: srv.Client()}
+
+	got, err := l.FetchProblem(context.Background(), "https://leetcode.com/problems/two-sum/description/?envType=daily")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &importer.Problem{
+		Title:       "Two Sum",
+		Difficulty:  "easy",
+		Tags:        []string{"Array", "Hash Table"},
+		Description: "Given `nums`, return a\\_i.",
+		URL:         "https://leetcode.com/problems/two-sum/",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FetchProblem() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFetchProblemTranslated(t *testing.T) {
+	srv := newServer(t, map[string]string{"two-sum": twoSum})
+	l := &LeetCode{Type: "leetcode-cn", Host: "leetcode.cn", Translated: true, BaseURL: srv.URL, Client: srv.Client()}
+
+	got, err := l.FetchProblem(context.Background(), "https://leetcode.cn/problems/two-sum/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "两数之和" || got.Description != "给定 `nums`" {
+		t.Errorf("FetchProblem() = %+v, want translated title and description", got)
+	}
+}
+
+func TestFetchProblemInContest(t *testing.T) {
+	srv := newServer(t, map[string]string{"two-sum": twoSum})
+	l := &LeetCode{Type: "leetcode", Host: "leetcode.com", BaseURL: srv.URL, Client: srv.Client()}
+
+	got, err := l.FetchProblem(context.Background(), "https://leetcode.com/contest/weekly-contest-1/problems/two-sum/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Contest != "Weekly Contest 1" || got.ContestType != "leetcode" {
+		t.Errorf("FetchProblem() contest = %q/%q, want Weekly Contest 1/leetcode", got.Contest, got.ContestType)
+	}
+}
+
+func TestFetchProblemNotFound(t *testing.T) {
+	srv := newServer(t, nil)
+	l := &LeetCode{Type: "leetcode", Host: "leetcode.com", BaseURL: srv.URL, Client: srv.Client()}
+
+	_, err := l.FetchProblem(context.Background(), "https://leetcode.com/problems/missing/")
+	if !errors.Is(err, importer.ErrNotFound) {
+		t.Errorf("FetchProblem() error = %v, want ErrNotFound", err)
+	}
+	_, err = l.FetchContest(context.Background(), "https://leetcode.com/contest/missing/")
+	if !errors.Is(err, importer.ErrNotFound) {
+		t.Errorf("FetchContest() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFetchContest(t *testing.T) {
+	srv := newServer(t, map[string]string{"two-sum": twoSum})
+	l := &LeetCode{Type: "leetcode", Host: "leetcode.com", BaseURL: srv.URL, Client: srv.Client()}
+
+	got, err := l.FetchContest(context.Background(), "https://leetcode.com/contest/weekly-contest-1/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "Weekly Contest 1" || got.URL != "https://leetcode.com/contest/weekly-contest-1/" {
+		t.Errorf("FetchContest() = %+v", got)
+	}
+	if len(got.Problems) != 1 || got.Problems[0].Title != "Two Sum" || got.Problems[0].Contest != got.Title {
+		t.Errorf("FetchContest() problems = %+v", got.Problems)
+	}
+
+	_, err = l.FetchContest(context.Background(), "https://leetcode.com/problems/two-sum/")
+	if !errors.Is(err, importer.ErrNotContest) {
+		t.Errorf("FetchContest() error = %v, want ErrNotContest", err)
+	}
+}
+
+func TestGraphQLError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"question":null},"errors":[{"message":"rate limited"}]}`))
+	}))
+	defer srv.Close()
+	l := &LeetCode{Type: "leetcode", Host: "leetcode.com", BaseURL: srv.URL, Client: srv.Client()}
+
+	_, err := l.FetchProblem(context.Background(), "https://leetcode.com/problems/two-sum/")
+	if err == nil || !strings.Contains(err.Error(), "rate limited") {
+		t.Errorf("FetchProblem() error = %v, want graphql error message", err)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	l := &LeetCode{Type: "leetcode", Host: "leetcode.com"}
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://leetcode.com/problems/two-sum/", true},
+		{"https://www.leetcode.com/problems/two-sum", true},
+		{"https://leetcode.com/contest/weekly-contest-1/", true},
+		{"https://leetcode.com/contest/weekly-contest-1/problems/two-sum/", true},
+		{"https://evilleetcode.com/problems/two-sum/", false},
+		{"https://leetcode.com.evil.io/problems/two-sum/", false},
+		{"https://leetcode.cn/problems/two-sum/", false},
+		{"https://leetcode.com/problemset/", false},
+	}
+	for _, tt := range tests {
+		if got := l.Match(tt.url); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
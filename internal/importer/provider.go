@@ -0,0 +1,88 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Provider 在线题库，Name 同时作为题目所属竞赛的类型
+type Provider interface {
+	// Name 竞赛类型，如 leetcode
+	Name() string
+	// Aliases 命令行中可使用的简写，如 l
+	Aliases() []string
+	// Match 判断链接是否属于该题库
+	Match(rawURL string) bool
+	// FetchProblem 获取题目信息，链接不是题目页面时返回 ErrUnsupportedURL
+	FetchProblem(ctx context.Context, rawURL string) (*Problem, error)
+	// FetchContest 获取链接所属比赛及其全部题目，不属于比赛时返回 ErrNotContest
+	FetchContest(ctx context.Context, rawURL string) (*Contest, error)
+}
+
+var (
+	mu        sync.RWMutex
+	providers []Provider
+)
+
+// Register 注册题库，名称或简写重复时 panic
+func Register(p Provider) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, key := range append([]string{p.Name()}, p.Aliases()...) {
+		if lookup(key) != nil {
+			panic("importer: duplicate provider " + key)
+		}
+	}
+	providers = append(providers, p)
+}
+
+// Providers 按注册顺序返回全部题库
+func Providers() []Provider {
+	mu.RLock()
+	defer mu.RUnlock()
+	return slices.Clone(providers)
+}
+
+// Names 返回全部题库名称，即合法的竞赛类型
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(providers))
+	for _, p := range providers {
+		names = append(names, p.Name())
+	}
+	return names
+}
+
+// Lookup 按名称或简写查找题库，忽略大小写
+func Lookup(name string) (Provider, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	p := lookup(name)
+	return p, p != nil
+}
+
+func lookup(name string) Provider {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, p := range providers {
+		if p.Name() == name || slices.Contains(p.Aliases(), name) {
+			return p
+		}
+	}
+	return nil
+}
+
+// ForURL 返回能处理该链接的题库
+func ForURL(rawURL string) (Provider, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, p := range providers {
+		if p.Match(rawURL) {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedURL, rawURL)
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AttemptVerdicts 一次提交可能的结果
+var AttemptVerdicts = []string{"AC", "WA", "TLE", "MLE", "RE", "CE"}
+
+// Attempt 一次做题尝试，记录结果、耗时与当时的代码快照
+type Attempt struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement:false;comment:主键"`
+	ProblemID int64     `gorm:"index;not null;comment:所属题目ID"`
+	Verdict   string    `gorm:"size:10;not null;comment:提交结果"`
+	Duration  int64     `gorm:"comment:耗时(秒)"`
+	Language  string    `gorm:"size:50;comment:语言"`
+	CodePath  string    `gorm:"text;comment:代码快照路径"`
+	Note      string    `gorm:"text;comment:备注"`
+	CreatedAt time.Time `gorm:"autoCreateTime;comment:提交时间"`
+}
+
+// ValidVerdict 校验并规范化提交结果
+func ValidVerdict(verdict string) (string, bool) {
+	verdict = strings.ToUpper(strings.TrimSpace(verdict))
+	return verdict, slices.Contains(AttemptVerdicts, verdict)
+}
+
+// SnapshotCode 将代码文件复制为一次尝试的快照，与题目代码一起存放在 CodeDir 中，返回快照路径
+func (p *Problem) SnapshotCode(src string, at time.Time) (string, error) {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return "", fmt.Errorf("read code file: %w", err)
+	}
+	base := p.auxPrefix() + "_attempt-" + at.Format("20060102T150405")
+	if err = os.MkdirAll(filepath.Dir(base), 0755); err != nil {
+		return "", fmt.Errorf("create code dir: %w", err)
+	}
+	// 同一秒内的多次尝试追加序号，不覆盖已有快照
+	for i := 1; ; i++ {
+		dst := base + filepath.Ext(src)
+		if i > 1 {
+			dst = fmt.Sprintf("%s-%d%s", base, i, filepath.Ext(src))
+		}
+		f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("write code snapshot: %w", err)
+		}
+		_, err = f.Write(data)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", fmt.Errorf("write code snapshot: %w", err)
+		}
+		return dst, nil
+	}
+}
+
+// DurationText 耗时的简短表示，如 45m、1h30m，未记录时为 -
+func (a *Attempt) DurationText() string {
+	if a.Duration <= 0 {
+		return "-"
+	}
+	s := (time.Duration(a.Duration) * time.Second).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
@@ -38,47 +38,122 @@ func (d *Difficulty) Valid() bool {
 	}
 }
 
-// Problem 题目
-type Problem struct {
-	ID          int64      `gorm:"primaryKey;autoIncrement:false;comment:主键"`       // 主键
-	Title       string     `gorm:"not null;size:255;comment:题目名"`                   // 题目名
-	Slug        string     `gorm:"uniqueIndex;not null;size:50;comment:题目短id、文件名用"` // 题目短id、文件名用
-	Difficulty  Difficulty `gorm:"not null;comment:题目难度"`                           // 题目难度
-	SolutionURL string     `gorm:"not null;text;comment:在线题目链接"`                    // 在线题目链接
-	Note        string     `gorm:"text;comment:题目笔记"`                               // 题目笔记
-	CodePath    string     `gorm:"text;comment:本地代码文件路径"`                           // 本地代码文件路径
-	Score       *uint8     `gorm:"comment:题目评分"`                                    // 题目分数
-	CreatedAt   time.Time  `gorm:"autoCreateTime;comment:创建时间"`                     // 创建时间
-	UpdatedAt   time.Time  `gorm:"autoUpdateTime;comment:更新时间"`                     // 更新时间
-	Tags        []*Tag     `gorm:"many2many:problem_tags;"`                         // 标签逻辑
-	Description string     `gorm:"text;comment:题目描述"`                               // 题目描述
-	ContestID   int64      `gorm:"index;comment:所属竞赛ID"`
-}
-
-// ContestType 竞赛类型
-type ContestType string
+// Status 做题状态
+type Status string
 
 const (
-	LEETCODE   ContestType = "leetcode"
-	CODEFORCES ContestType = "codeforces"
+	StatusTodo        Status = "todo"         // 待做，可以没有代码
+	StatusAttempted   Status = "attempted"    // 尝试过但未通过
+	StatusSolved      Status = "solved"       // 已通过
+	StatusNeedsReview Status = "needs-review" // 已通过但需要复习
 )
 
-func (d *ContestType) String() string {
-	return string(*d)
+// Statuses 全部做题状态
+var Statuses = []Status{StatusTodo, StatusAttempted, StatusSolved, StatusNeedsReview}
+
+// SolvedStatuses 视为已解决的状态，统计、生成文档与复习只包含这些题目
+var SolvedStatuses = []Status{StatusSolved, StatusNeedsReview}
+
+func (s *Status) Valid() bool {
+	toLower := Status(strings.ToLower(string(*s)))
+	for _, status := range Statuses {
+		if toLower == status {
+			*s = toLower
+			return true
+		}
+	}
+	return false
+}
+
+// Solved 是否视为已解决
+func (s Status) Solved() bool {
+	return s == StatusSolved || s == StatusNeedsReview
+}
+
+// AfterAttempt 记录一次尝试后的状态：通过后未解决的题目变为 solved，未通过的待做题目变为 attempted
+func (s Status) AfterAttempt(verdict string) Status {
+	switch {
+	case verdict == "AC" && !s.Solved():
+		return StatusSolved
+	case verdict != "AC" && s == StatusTodo:
+		return StatusAttempted
+	}
+	return s
 }
 
-func GetContestType(s string) (ContestType, error) {
-	toLower := strings.ToLower(s)
+// AfterReview 复习后的状态：没有记住的题目需要复习，需要复习的题目记住后重新视为 solved
+func (s Status) AfterReview(recalled bool) Status {
+	switch {
+	case !recalled && s == StatusSolved:
+		return StatusNeedsReview
+	case recalled && s == StatusNeedsReview:
+		return StatusSolved
+	}
+	return s
+}
+
+// CheckerMode 答案检查方式
+type CheckerMode string
+
+const (
+	CheckerExact  CheckerMode = "exact"  // 逐行比较，忽略行尾空白
+	CheckerTokens CheckerMode = "tokens" // 按空白分隔逐个比较
+	CheckerFloat  CheckerMode = "float"  // 数值按绝对或相对误差比较
+	CheckerCustom CheckerMode = "custom" // testlib 风格的检查程序
+)
+
+func (c *CheckerMode) Valid() bool {
+	toLower := CheckerMode(strings.ToLower(string(*c)))
 	switch toLower {
-	case "leetcode", "l":
-		return LEETCODE, nil
-	case "codeforces", "c":
-		return CODEFORCES, nil
+	case CheckerExact, CheckerTokens, CheckerFloat, CheckerCustom:
+		*c = toLower
+		return true
 	default:
-		return LEETCODE, fmt.Errorf("未找到匹配的类型")
+		return false
 	}
 }
 
+// Problem 题目
+type Problem struct {
+	ID          int64       `gorm:"primaryKey;autoIncrement:false;comment:主键"`       // 主键
+	Title       string      `gorm:"not null;size:255;comment:题目名"`                   // 题目名
+	Slug        string      `gorm:"uniqueIndex;not null;size:50;comment:题目短id、文件名用"` // 题目短id、文件名用
+	Difficulty  Difficulty  `gorm:"not null;comment:题目难度"`                           // 题目难度
+	SolutionURL string      `gorm:"not null;text;comment:在线题目链接"`                    // 在线题目链接
+	Note        string      `gorm:"text;comment:题目笔记"`                               // 题目笔记
+	CodePath    string      `gorm:"text;comment:本地代码文件路径"`                           // 本地代码文件路径
+	Score       *uint8      `gorm:"comment:题目评分"`                                    // 题目分数
+	CreatedAt   time.Time   `gorm:"autoCreateTime;comment:创建时间"`                     // 创建时间
+	UpdatedAt   time.Time   `gorm:"autoUpdateTime;comment:更新时间"`                     // 更新时间
+	Tags        []*Tag      `gorm:"many2many:problem_tags;"`                         // 标签逻辑
+	Description string      `gorm:"text;comment:题目描述"`                               // 题目描述
+	ContestID   int64       `gorm:"index;comment:所属竞赛ID"`
+	Rating      *int        `gorm:"comment:在线题库难度分"` // 如 Codeforces rating
+	TimeLimit   int         `gorm:"comment:时间限制(毫秒)"`
+	MemoryLimit int         `gorm:"comment:内存限制(MB)"`
+	Tests       []*TestCase `gorm:"foreignKey:ProblemID;constraint:OnDelete:CASCADE;"` // 测试用例
+	Attempts    []*Attempt  `gorm:"foreignKey:ProblemID;constraint:OnDelete:CASCADE;"` // 做题尝试
+	Checker     CheckerMode `gorm:"size:20;comment:答案检查方式"`                            // 为空时按 exact 比较
+	Epsilon     float64     `gorm:"comment:浮点比较的误差"`                                   // 为 0 时使用默认误差
+	Status      Status      `gorm:"size:20;not null;default:solved;index;comment:做题状态"`
+	SolvedAt    *time.Time  `gorm:"index;comment:首次解决时间"` // 未解决过时为空
+}
+
+// SetStatus 修改做题状态，第一次变为已解决时记录解决时间
+func (p *Problem) SetStatus(status Status, at time.Time) {
+	p.Status = status
+	if status.Solved() && p.SolvedAt == nil {
+		p.SolvedAt = &at
+	}
+}
+
+// ContestType 竞赛类型，取值为已注册的在线题库名称，如 leetcode、codeforces
+type ContestType string
+
+func (d *ContestType) String() string {
+	return string(*d)
+}
+
 // Contest 竞赛
 type Contest struct {
 	ID       int64       `gorm:"primaryKey;autoIncrement:false;comment:主键"`
@@ -114,31 +189,102 @@ func (p *Problem) SetSlug() {
 	p.Slug = fmt.Sprintf("%04d_%s", p.ID, slug)
 }
 
-func (p *Problem) SetCodePath() {
+// CopyCode 将 CodePath 指向的代码文件复制到 CodeDir，并更新 CodePath
+func (p *Problem) CopyCode() error {
 	data, err := os.ReadFile(p.CodePath)
 	if err != nil {
-		util.GetLog().Error("read code file error")
-		panic(err)
+		util.GetLog().Error("read code file error", zap.Error(err))
+		return fmt.Errorf("read code file: %w", err)
 	}
-	// 2. 获取目标目录
+	return p.SaveCode(data, filepath.Ext(p.CodePath)) // 保留原始文件扩展名
+}
+
+// CodeFile 题目代码在 CodeDir 中的路径，ext 为带点的扩展名
+func (p *Problem) CodeFile(ext string) string {
+	return filepath.Join(config.GetConfig().Dir.CodeDir, fmt.Sprintf("%s_code%s", p.Slug, ext))
+}
+
+// SaveCode 将代码内容写入 CodeDir，ext 为带点的扩展名，并更新 CodePath
+func (p *Problem) SaveCode(data []byte, ext string) error {
+	// 获取目标目录
 	codeDir := config.GetConfig().Dir.CodeDir
 	// 确保目录存在
-	if err = os.MkdirAll(codeDir, 0755); err != nil {
+	if err := os.MkdirAll(codeDir, 0755); err != nil {
 		util.GetLog().Error("create code dir error", zap.Error(err))
-		panic(err)
+		return fmt.Errorf("create code dir: %w", err)
 	}
 
-	// 3. 构建新文件名和路径
-	ext := filepath.Ext(p.CodePath) // 保留原始文件扩展名
-	newFileName := fmt.Sprintf("%s_code%s", p.Slug, ext)
-	dstPath := filepath.Join(codeDir, newFileName)
-	if err = os.WriteFile(dstPath, data, 0644); err != nil {
+	dstPath := p.CodeFile(ext)
+	if err := os.WriteFile(dstPath, data, 0644); err != nil {
 		util.GetLog().Error("write code file error", zap.Error(err))
-		panic(err)
+		return fmt.Errorf("write code file: %w", err)
 	}
 
-	// 5. 更新 Problem 的 CodePath 为新路径
+	// 更新 Problem 的 CodePath 为新路径
 	p.CodePath = dstPath
+	return nil
+}
+
+// 辅助代码文件的类型，与题目代码一起存放在 CodeDir 中
+const (
+	AuxGenerator  = "gen"        // 随机数据生成器
+	AuxBrute      = "brute"      // 暴力解法
+	AuxChecker    = "checker"    // 自定义检查程序
+	AuxInteractor = "interactor" // 交互题的交互器
+)
+
+// AuxKinds 全部辅助代码类型
+var AuxKinds = []string{AuxGenerator, AuxBrute, AuxChecker, AuxInteractor}
+
+// auxPrefix 辅助代码文件名前缀，与 CodePath 去掉 _code 与扩展名后相同
+func (p *Problem) auxPrefix() string {
+	if p.CodePath == "" {
+		return filepath.Join(config.GetConfig().Dir.CodeDir, p.Slug)
+	}
+	prefix := strings.TrimSuffix(p.CodePath, filepath.Ext(p.CodePath))
+	return strings.TrimSuffix(prefix, "_code")
+}
+
+// AuxPath 查找题目的辅助代码文件，不存在时返回空字符串
+func (p *Problem) AuxPath(kind string) string {
+	matches, _ := filepath.Glob(p.auxPrefix() + "_" + kind + ".*")
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[0]
+}
+
+// SaveAux 复制本地文件作为题目的辅助代码，替换已有的同类文件，返回新路径
+func (p *Problem) SaveAux(kind, src string) (string, error) {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return "", fmt.Errorf("read %s file: %w", kind, err)
+	}
+	if old := p.AuxPath(kind); old != "" {
+		if err = os.Remove(old); err != nil {
+			return "", fmt.Errorf("remove %s file: %w", kind, err)
+		}
+	}
+	dst := p.auxPrefix() + "_" + kind + filepath.Ext(src)
+	if err = os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return "", fmt.Errorf("create code dir: %w", err)
+	}
+	if err = os.WriteFile(dst, data, 0644); err != nil {
+		return "", fmt.Errorf("write %s file: %w", kind, err)
+	}
+	return dst, nil
+}
+
+// RemoveAux 删除题目的辅助代码文件，返回删除的路径，不存在时返回空字符串
+func (p *Problem) RemoveAux(kind string) (string, error) {
+	path := p.AuxPath(kind)
+	if path == "" {
+		return "", nil
+	}
+	if err := os.Remove(path); err != nil {
+		return "", fmt.Errorf("remove %s file: %w", kind, err)
+	}
+	return path, nil
 }
 
 // Tag 题目标签
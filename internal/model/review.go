@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Review 题目的间隔重复复习计划（SM-2），题目第一次复习时创建
+type Review struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement:false;comment:主键"`
+	ProblemID   int64     `gorm:"uniqueIndex;not null;comment:所属题目ID"`
+	Ease        float64   `gorm:"not null;comment:难度系数"`
+	Interval    int       `gorm:"comment:复习间隔(天)"`
+	Repetitions int       `gorm:"comment:连续记住的次数"`
+	DueAt       time.Time `gorm:"index;comment:下次复习时间"`
+	ReviewedAt  time.Time `gorm:"comment:上次复习时间"`
+	CreatedAt   time.Time `gorm:"autoCreateTime;comment:创建时间"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime;comment:更新时间"`
+}
+
+// ReviewLog 一次复习的评分，用于统计记忆保持率
+type ReviewLog struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement:false;comment:主键"`
+	ProblemID int64     `gorm:"index;not null;comment:所属题目ID"`
+	Grade     int       `gorm:"not null;comment:评分(0~5)"`
+	Ease      float64   `gorm:"comment:复习后的难度系数"`
+	Interval  int       `gorm:"comment:复习后的间隔(天)"`
+	CreatedAt time.Time `gorm:"autoCreateTime;comment:复习时间"`
+}
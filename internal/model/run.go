@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// Run 一次测试用例的运行记录，用于查看解法距离时间与内存限制还有多少余量
+type Run struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement:false;comment:主键"`
+	ProblemID   int64     `gorm:"index;not null;comment:所属题目ID"`
+	TestCaseID  int64     `gorm:"index;comment:测试用例ID"`
+	Verdict     string    `gorm:"size:10;not null;comment:评测结果"`
+	TimeMs      int64     `gorm:"comment:墙钟时间(毫秒)"`
+	CPUMs       int64     `gorm:"comment:CPU 时间(毫秒)"`
+	MemoryKB    int64     `gorm:"comment:峰值内存(KB)"`
+	TimeLimit   int64     `gorm:"comment:时间限制(毫秒)"`
+	MemoryLimit int64     `gorm:"comment:内存限制(MB)"`
+	CreatedAt   time.Time `gorm:"autoCreateTime;comment:运行时间"`
+}
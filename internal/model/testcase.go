@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// TestCase 题目的测试用例，Sample 表示来自题面样例
+type TestCase struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement:false;comment:主键"`
+	ProblemID int64     `gorm:"index;not null;comment:所属题目ID"`
+	Input     string    `gorm:"text;comment:输入"`
+	Output    string    `gorm:"text;comment:期望输出"`
+	Sample    bool      `gorm:"comment:是否为题面样例"`
+	CreatedAt time.Time `gorm:"autoCreateTime;comment:创建时间"`
+}
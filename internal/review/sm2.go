@@ -0,0 +1,52 @@
+// Package review 间隔重复复习的调度算法
+package review
+
+import "math"
+
+const (
+	DefaultEase = 2.5 // 新题目的难度系数
+	MinEase     = 1.3 // 难度系数下限
+	MaxGrade    = 5   // 评分范围 0~5
+	PassGrade   = 3   // 不低于该评分视为记住
+)
+
+// State 一道题目的复习状态
+type State struct {
+	Ease        float64 // 难度系数
+	Interval    int     // 复习间隔，天
+	Repetitions int     // 连续记住的次数
+}
+
+// New 尚未复习过的题目的状态
+func New() State {
+	return State{Ease: DefaultEase}
+}
+
+// SM2 按 SuperMemo-2 算法根据评分计算下一次的复习状态：记住时间隔依次为 1、6 天，
+// 之后乘以难度系数；没记住时从 1 天重新开始。难度系数随评分调整，不低于 MinEase
+func SM2(s State, grade int) State {
+	if s.Ease == 0 {
+		s.Ease = DefaultEase
+	}
+	if grade >= PassGrade {
+		switch s.Repetitions {
+		case 0:
+			s.Interval = 1
+		case 1:
+			s.Interval = 6
+		default:
+			s.Interval = int(math.Round(float64(s.Interval) * s.Ease))
+		}
+		s.Repetitions++
+	} else {
+		s.Repetitions, s.Interval = 0, 1
+	}
+	miss := float64(MaxGrade - grade)
+	s.Ease = max(MinEase, s.Ease+0.1-miss*(0.08+miss*0.02))
+	return s
+}
+
+// Recalled 评分是否视为记住
+func Recalled(grade int) bool {
+	return grade >= PassGrade
+}
@@ -0,0 +1,109 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultEpsilon 浮点比较未指定误差时使用
+const DefaultEpsilon = 1e-6
+
+// Checker 判断程序输出是否正确，message 为不正确时的说明，err 表示检查本身出错
+type Checker interface {
+	Check(ctx context.Context, input, expected, actual string) (ok bool, message string, err error)
+}
+
+// ExactChecker 逐行比较，忽略行尾空白与末尾空行
+type ExactChecker struct{}
+
+func (ExactChecker) Check(_ context.Context, _, expected, actual string) (bool, string, error) {
+	return Check(expected, actual), "", nil
+}
+
+// TokenChecker 按空白分隔后逐个比较，忽略换行与空格数量
+type TokenChecker struct{}
+
+func (TokenChecker) Check(_ context.Context, _, expected, actual string) (bool, string, error) {
+	ok, message := compareTokens(expected, actual, func(want, got string) bool { return want == got })
+	return ok, message, nil
+}
+
+// FloatChecker 按空白分隔后逐个比较，数值的绝对或相对误差不超过 Epsilon 即视为相等
+type FloatChecker struct {
+	Epsilon float64
+}
+
+func (c FloatChecker) Check(_ context.Context, _, expected, actual string) (bool, string, error) {
+	eps := c.Epsilon
+	if eps <= 0 {
+		eps = DefaultEpsilon
+	}
+	ok, message := compareTokens(expected, actual, func(want, got string) bool {
+		w, err1 := strconv.ParseFloat(want, 64)
+		g, err2 := strconv.ParseFloat(got, 64)
+		if err1 != nil || err2 != nil {
+			return want == got
+		}
+		diff := math.Abs(w - g)
+		return diff <= eps || diff <= eps*math.Abs(w)
+	})
+	return ok, message, nil
+}
+
+func compareTokens(expected, actual string, equal func(want, got string) bool) (bool, string) {
+	want, got := strings.Fields(expected), strings.Fields(actual)
+	for i := 0; i < min(len(want), len(got)); i++ {
+		if !equal(want[i], got[i]) {
+			return false, fmt.Sprintf("token %d: expected %q, found %q", i+1, want[i], got[i])
+		}
+	}
+	if len(want) != len(got) {
+		return false, fmt.Sprintf("expected %d tokens, found %d", len(want), len(got))
+	}
+	return true, ""
+}
+
+// ProgramChecker testlib 风格的检查程序，以 <input> <output> <answer> 三个文件路径为参数，
+// 退出码 0 表示正确，1 或 2 表示答案错误，其余视为检查程序出错
+type ProgramChecker struct {
+	Program *Program
+	Limits  Limits
+}
+
+func (c *ProgramChecker) Check(ctx context.Context, input, expected, actual string) (bool, string, error) {
+	dir, err := os.MkdirTemp("", "algo-check-")
+	if err != nil {
+		return false, "", err
+	}
+	defer os.RemoveAll(dir)
+	files := []struct{ name, data string }{{"input.txt", input}, {"output.txt", actual}, {"answer.txt", expected}}
+	args := make([]string, 0, len(files))
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		if err = os.WriteFile(path, []byte(f.data), 0644); err != nil {
+			return false, "", err
+		}
+		args = append(args, path)
+	}
+
+	result := c.Program.Run(ctx, "", c.Limits, args...)
+	message := strings.TrimSpace(result.Stderr + result.Output)
+	var exit *exec.ExitError
+	switch {
+	case result.Verdict == AC:
+		return true, message, nil
+	case result.Verdict == TLE:
+		return false, "", errors.New("checker timed out")
+	case errors.As(result.Err, &exit) && (exit.ExitCode() == 1 || exit.ExitCode() == 2):
+		return false, message, nil
+	default:
+		return false, "", fmt.Errorf("checker failed: %v %s", result.Err, message)
+	}
+}
@@ -0,0 +1,112 @@
+package runner
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckers(t *testing.T) {
+	tests := []struct {
+		name             string
+		checker          Checker
+		expected, actual string
+		want             bool
+		message          string
+	}{
+		{"exact equal", ExactChecker{}, "1 2\n", "1 2  \n\n", true, ""},
+		{"exact spacing", ExactChecker{}, "1 2\n", "1  2\n", false, ""},
+		{"exact lines", ExactChecker{}, "1 2\n", "1\n2\n", false, ""},
+		{"token spacing", TokenChecker{}, "1 2\n", "1  2", true, ""},
+		{"token lines", TokenChecker{}, "1 2\n", "1\n2\n", true, ""},
+		{"token differ", TokenChecker{}, "1 2 3", "1 5 3", false, `token 2: expected "2", found "5"`},
+		{"token fewer", TokenChecker{}, "1 2 3", "1 2", false, "expected 3 tokens, found 2"},
+		{"token more", TokenChecker{}, "1 2", "1 2 3", false, "expected 2 tokens, found 3"},
+		{"token case", TokenChecker{}, "YES", "yes", false, `token 1: expected "YES", found "yes"`},
+		{"float exact", FloatChecker{}, "0.5", "0.500000", true, ""},
+		{"float default epsilon", FloatChecker{}, "1.0", "1.0000009", true, ""},
+		{"float beyond default", FloatChecker{}, "1.0", "1.00001", false, `token 1: expected "1.0", found "1.00001"`},
+		{"float absolute", FloatChecker{Epsilon: 1e-3}, "0", "0.0009", true, ""},
+		{"float relative", FloatChecker{Epsilon: 1e-6}, "1000000", "1000000.9", true, ""},
+		{"float relative beyond", FloatChecker{Epsilon: 1e-6}, "1000000", "1000002", false, `token 1: expected "1000000", found "1000002"`},
+		{"float negative", FloatChecker{Epsilon: 1e-3}, "-2.5", "-2.5004", true, ""},
+		{"float words", FloatChecker{}, "area 3.14", "area 3.1400001", true, ""},
+		{"float word differ", FloatChecker{}, "YES 1", "NO 1", false, `token 1: expected "YES", found "NO"`},
+		{"float count", FloatChecker{}, "1 2", "1", false, "expected 2 tokens, found 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, message, err := tt.checker.Check(context.Background(), "", tt.expected, tt.actual)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.want || message != tt.message {
+				t.Errorf("Check(%q, %q) = %v, %q, want %v, %q", tt.expected, tt.actual, ok, message, tt.want, tt.message)
+			}
+		})
+	}
+}
+
+func TestProgramChecker(t *testing.T) {
+	// 检查程序按参数读取三个文件，输出与答案相同时通过，否则按输入中的退出码退出
+	checker := &ProgramChecker{
+		Program: compileScript(t, `
+if [ "$(cat "$2")" = "$(cat "$3")" ]; then echo ok >&2; exit 0; fi
+code=$(cat "$1")
+if [ "$code" = hang ]; then sleep 5; fi
+echo "differ on $(basename "$2")" >&2
+exit "$code"
+`),
+		Limits: Limits{Time: 300 * time.Millisecond},
+	}
+	tests := []struct {
+		name    string
+		input   string
+		actual  string
+		want    bool
+		message string
+		err     string
+	}{
+		{"accepted", "1", "42", true, "ok", ""},
+		{"wrong answer", "1", "41", false, "differ on output.txt", ""},
+		{"presentation error", "2", "41", false, "differ on output.txt", ""},
+		{"checker failure", "3", "41", false, "", "checker failed"},
+		{"checker crash", "139", "41", false, "", "checker failed"},
+		{"checker timeout", "hang", "41", false, "", "checker timed out"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, message, err := checker.Check(context.Background(), tt.input, "42", tt.actual)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("Check() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.want || message != tt.message {
+				t.Errorf("Check() = %v, %q, want %v, %q", ok, message, tt.want, tt.message)
+			}
+		})
+	}
+}
+
+func TestTestWithChecker(t *testing.T) {
+	p := compileScript(t, "echo 0.3333334")
+	limits := Limits{Time: time.Second}
+	if result := p.Test(context.Background(), "", "0.333333", limits, nil); result.Verdict != WA {
+		t.Errorf("Test() with exact checker = %s, want WA", result.Verdict)
+	}
+	if result := p.Test(context.Background(), "", "0.333333", limits, FloatChecker{}); result.Verdict != AC {
+		t.Errorf("Test() with float checker = %s, want AC", result.Verdict)
+	}
+
+	failing := &ProgramChecker{Program: compileScript(t, "exit 7"), Limits: limits}
+	result := p.Test(context.Background(), "", "0.333333", limits, failing)
+	if result.Verdict != FAIL || result.Err == nil {
+		t.Errorf("Test() with failing checker = %s, %v, want FAIL", result.Verdict, result.Err)
+	}
+}
@@ -0,0 +1,206 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxTranscript 通信记录最多保留的字节数
+const maxTranscript = 1 << 20
+
+// Interact 运行交互题：程序与交互器的标准输入输出通过管道相连。交互器以 <input> <output> <answer>
+// 三个文件路径为参数（testlib 约定），退出码 0 表示正确，1 或 2 表示答案错误，其余视为交互器出错。
+// 交互器先于程序结束且未判定正确时以交互器的结果为准，否则程序自身的 TLE/MLE/RE 优先。
+// 结果的 Output 为双方的通信记录，"> " 开头的行由程序发出，"< " 开头的行由交互器发出，
+// Message 为交互器的标准错误输出
+func (p *Program) Interact(ctx context.Context, interactor *Program, input, answer string, limits, interactorLimits Limits) *Result {
+	dir, err := os.MkdirTemp("", "algo-interact-")
+	if err != nil {
+		return &Result{Verdict: FAIL, Err: err}
+	}
+	defer os.RemoveAll(dir)
+	files := []struct{ name, data string }{{"input.txt", input}, {"output.txt", ""}, {"answer.txt", answer}}
+	args := make([]string, 0, len(files))
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		if err = os.WriteFile(path, []byte(f.data), 0644); err != nil {
+			return &Result{Verdict: FAIL, Err: err}
+		}
+		args = append(args, path)
+	}
+
+	// 程序与交互器之间各有两段管道，中间由 relay 转发并记录
+	var pipes [8]*os.File
+	defer func() {
+		for _, f := range pipes {
+			if f != nil {
+				f.Close()
+			}
+		}
+	}()
+	for i := 0; i < len(pipes); i += 2 {
+		if pipes[i], pipes[i+1], err = os.Pipe(); err != nil {
+			return &Result{Verdict: FAIL, Err: err}
+		}
+	}
+	solutionIn, toSolution := pipes[0], pipes[1]
+	fromSolution, solutionOut := pipes[2], pipes[3]
+	interactorIn, toInteractor := pipes[4], pipes[5]
+	fromInteractor, interactorOut := pipes[6], pipes[7]
+
+	// 标准错误写入文件，避免后台子进程占用管道时等待
+	solutionErr, err := os.Create(filepath.Join(dir, "solution.err"))
+	if err != nil {
+		return &Result{Verdict: FAIL, Err: err}
+	}
+	defer solutionErr.Close()
+	interactorErr, err := os.Create(filepath.Join(dir, "interactor.err"))
+	if err != nil {
+		return &Result{Verdict: FAIL, Err: err}
+	}
+	defer interactorErr.Close()
+
+	solutionCtx, cancelSolution := withDeadline(ctx, limits)
+	defer cancelSolution()
+	solution := p.command(solutionCtx)
+	solution.Stdin, solution.Stdout, solution.Stderr = solutionIn, solutionOut, solutionErr
+	interactorCtx, cancelInteractor := withDeadline(ctx, interactorLimits)
+	defer cancelInteractor()
+	judge := interactor.command(interactorCtx, args...)
+	judge.Stdin, judge.Stdout, judge.Stderr = interactorIn, interactorOut, interactorErr
+
+	solutionProcess := startProcess(solutionCtx, solution, limits)
+	interactorProcess := startProcess(interactorCtx, judge, interactorLimits)
+	// 关闭子进程一侧的管道，任一方退出后另一方才能读到 EOF
+	for _, i := range []int{0, 3, 4, 7} {
+		pipes[i].Close()
+		pipes[i] = nil
+	}
+
+	t := &transcript{}
+	var solutionEnd, interactorEnd relayEnd
+	var relays sync.WaitGroup
+	relays.Add(2)
+	go t.relay(&relays, toInteractor, fromSolution, "> ", &solutionEnd)
+	go t.relay(&relays, toSolution, fromInteractor, "< ", &interactorEnd)
+
+	var result, judged *Result
+	var processes sync.WaitGroup
+	processes.Add(2)
+	go func() {
+		defer processes.Done()
+		result = solutionProcess.wait()
+	}()
+	go func() {
+		defer processes.Done()
+		judged = interactorProcess.wait()
+	}()
+	processes.Wait()
+	relays.Wait()
+	pipes[1], pipes[5] = nil, nil // 已由 relay 关闭
+
+	result.Output, result.Stderr = t.String(), readOutput(solutionErr)
+	result.Message = strings.TrimSpace(readOutput(interactorErr))
+	// 交互器先结束时程序随后会读到 EOF、因写入失败收到 SIGPIPE 或一直等待，此时程序的异常不是真正的原因。
+	// 一方的输出结束后另一方才会读到 EOF，按两段转发结束的先后判断，不受进程回收顺序影响
+	interactorFirst := solutionEnd.broken || interactorEnd.at.Before(solutionEnd.at)
+	if result.Verdict != AC && !(judged.Verdict != AC && interactorFirst) {
+		return result
+	}
+	var exit *exec.ExitError
+	switch {
+	case judged.Verdict == AC:
+	case errors.As(judged.Err, &exit) && (exit.ExitCode() == 1 || exit.ExitCode() == 2):
+		result.Verdict, result.Err = WA, nil
+	case judged.Verdict == TLE:
+		result.Verdict, result.Err = FAIL, errors.New("interactor timed out")
+	default:
+		result.Verdict, result.Err = FAIL, fmt.Errorf("interactor failed: %v", judged.Err)
+	}
+	return result
+}
+
+// transcript 交互双方按行交替的通信记录
+type transcript struct {
+	mu        sync.Mutex
+	buf       strings.Builder
+	truncated bool
+}
+
+// relayEnd 一段转发结束的时间，broken 表示因对方已关闭输入而写入失败
+type relayEnd struct {
+	at     time.Time
+	broken bool
+}
+
+// relay 将 src 读到的内容立即转发给 dst 并按行记录，src 结束后关闭 dst。
+// dst 已关闭时同时关闭 src，写入方随即收到 SIGPIPE，不会在对方退出后一直运行到超时
+func (t *transcript) relay(wg *sync.WaitGroup, dst io.WriteCloser, src io.ReadCloser, prefix string, end *relayEnd) {
+	defer wg.Done()
+	defer dst.Close()
+	var line []byte
+	buf := make([]byte, 32<<10)
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			// 先记录再转发，对方的回复一定记在之后
+			line = append(line, buf[:n]...)
+			for {
+				i := bytes.IndexByte(line, '\n')
+				if i < 0 {
+					break
+				}
+				t.add(prefix, line[:i])
+				line = line[i+1:]
+			}
+			if len(line) > maxTranscript {
+				t.add(prefix, line)
+				line = nil
+			}
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				end.broken = true
+				src.Close()
+				break
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	// 先记录结束时间再关闭 dst，对方读到 EOF 一定在此之后
+	end.at = time.Now()
+	if len(line) > 0 {
+		t.add(prefix, line)
+	}
+}
+
+func (t *transcript) add(prefix string, line []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.truncated {
+		return
+	}
+	if t.buf.Len()+len(prefix)+len(line) >= maxTranscript {
+		t.truncated = true
+		t.buf.WriteString("...\n")
+		return
+	}
+	t.buf.WriteString(prefix)
+	t.buf.Write(bytes.TrimRight(line, "\r"))
+	t.buf.WriteByte('\n')
+}
+
+func (t *transcript) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.buf.String()
+}
@@ -0,0 +1,103 @@
+package runner
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// guessInteractor 猜数交互器：从 <input> 读取答案，回复 < > =，10 次内猜中时退出码为 0，否则为 1
+const guessInteractor = `
+n=$(cat "$1")
+i=0
+while [ $i -lt 10 ]; do
+	i=$((i + 1))
+	read g || { echo "unexpected eof" >&2; exit 2; }
+	if [ "$g" -lt "$n" ]; then echo "<"
+	elif [ "$g" -gt "$n" ]; then echo ">"
+	else echo "="; echo "guessed in $i" >&2; exit 0
+	fi
+done
+echo "too many guesses" >&2
+exit 1
+`
+
+// binarySearch 二分猜数，每次回复后再猜
+const binarySearch = `
+lo=1; hi=100
+while :; do
+	mid=$(((lo + hi) / 2))
+	echo $mid
+	read r
+	case "$r" in
+		"=") exit 0 ;;
+		"<") lo=$((mid + 1)) ;;
+		">") hi=$((mid - 1)) ;;
+	esac
+done
+`
+
+func TestInteract(t *testing.T) {
+	interactor := compileScript(t, guessInteractor)
+	limits := Limits{Time: 500 * time.Millisecond}
+	// 交互器的限制更宽，程序超时时交互器仍在等待输入
+	interactorLimits := Limits{Time: 5 * time.Second}
+	tests := []struct {
+		name     string
+		solution string
+		want     Verdict
+		message  string
+	}{
+		{"accepted", binarySearch, AC, "guessed in 7"},
+		{"wrong answer", "while :; do echo 1; read r; done", WA, "too many guesses"},
+		{"early exit", "exit 0", WA, "unexpected eof"},
+		{"runtime error", "echo 50; read r; exit 3", RE, "unexpected eof"},
+		{"time limit", "sleep 10", TLE, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			solution := compileScript(t, tt.solution)
+			result := solution.Interact(context.Background(), interactor, "42\n", "", limits, interactorLimits)
+			if result.Verdict != tt.want {
+				t.Errorf("Interact() verdict = %s, want %s (err %v, message %q)", result.Verdict, tt.want, result.Err, result.Message)
+			}
+			if tt.message != "" && result.Message != tt.message {
+				t.Errorf("Interact() message = %q, want %q", result.Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestInteractTranscript(t *testing.T) {
+	interactor := compileScript(t, guessInteractor)
+	solution := compileScript(t, "echo 10; read r; echo 42; read r")
+	limits := Limits{Time: 500 * time.Millisecond}
+
+	result := solution.Interact(context.Background(), interactor, "42\n", "", limits, limits)
+	if result.Verdict != AC {
+		t.Fatalf("Interact() verdict = %s, want AC (err %v)", result.Verdict, result.Err)
+	}
+	want := "> 10\n< <\n> 42\n< =\n"
+	if result.Output != want {
+		t.Errorf("Interact() transcript = %q, want %q", result.Output, want)
+	}
+}
+
+func TestInteractorFailure(t *testing.T) {
+	solution := compileScript(t, binarySearch)
+	limits := Limits{Time: 500 * time.Millisecond}
+
+	crashed := compileScript(t, `echo "bad input" >&2; exit 3`)
+	result := solution.Interact(context.Background(), crashed, "42\n", "", limits, limits)
+	if result.Verdict != FAIL || result.Err == nil || !strings.Contains(result.Err.Error(), "interactor failed") {
+		t.Errorf("Interact() = %s, %v, want FAIL with interactor failed", result.Verdict, result.Err)
+	}
+
+	// 交互器不读取也不退出，程序等待回复，交互器超时
+	hung := compileScript(t, "sleep 10")
+	result = solution.Interact(context.Background(), hung, "42\n", "", Limits{Time: 10 * time.Second}, Limits{Time: 200 * time.Millisecond})
+	if result.Verdict != FAIL || result.Err == nil || !strings.Contains(result.Err.Error(), "interactor timed out") {
+		t.Errorf("Interact() = %s, %v, want FAIL with interactor timed out", result.Verdict, result.Err)
+	}
+}
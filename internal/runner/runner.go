@@ -0,0 +1,427 @@
+// Package runner 编译并在资源限制下运行本地代码，按测试用例给出 AC/WA/TLE/MLE/RE 结果
+package runner
+
+import (
+	"algo/pkg/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Verdict 评测结果
+type Verdict string
+
+const (
+	AC   Verdict = "AC"   // 答案正确
+	WA   Verdict = "WA"   // 答案错误
+	TLE  Verdict = "TLE"  // 超出时间限制
+	MLE  Verdict = "MLE"  // 超出内存限制
+	RE   Verdict = "RE"   // 运行错误
+	CE   Verdict = "CE"   // 编译错误
+	FAIL Verdict = "FAIL" // 检查程序出错
+)
+
+// Language 编译与运行命令模板，{src} 为源文件，{bin} 为可执行文件，{dir} 为工作目录，{name} 为不含扩展名的源文件名
+type Language struct {
+	Ext            string
+	Name           string // Markdown 代码块与统计使用的语言名
+	Compile        string // 为空表示解释执行
+	Run            string
+	Source         string  // 源文件需要的固定文件名，如 Java 的 Main.java
+	TimeMultiplier float64 // 时间限制倍数
+}
+
+// 内置语言，可在 algo.toml 的 [LANGUAGES.<扩展名>] 中覆盖
+var builtin = map[string]Language{
+	"cpp":  {Name: "cpp", Compile: "g++ -O2 -std=c++17 {src} -o {bin}", Run: "{bin}"},
+	"cc":   {Name: "cpp", Compile: "g++ -O2 -std=c++17 {src} -o {bin}", Run: "{bin}"},
+	"c":    {Name: "c", Compile: "gcc -O2 {src} -o {bin} -lm", Run: "{bin}"},
+	"go":   {Name: "go", Compile: "go build -o {bin} {src}", Run: "{bin}"},
+	"rs":   {Name: "rust", Compile: "rustc -O {src} -o {bin}", Run: "{bin}"},
+	"java": {Name: "java", Compile: "javac -d {dir} {src}", Run: "java -cp {dir} Main", Source: "Main.java", TimeMultiplier: 2},
+	"py":   {Name: "python", Run: "python3 {src}", TimeMultiplier: 3},
+	"js":   {Name: "javascript", Run: "node {src}", TimeMultiplier: 2},
+}
+
+// LookupLanguage 按扩展名查找语言，配置文件优先于内置语言，ext 可带点
+func LookupLanguage(ext string) (Language, bool) {
+	ext = strings.ToLower(strings.TrimPrefix(ext, "."))
+	for key, l := range config.GetConfig().Languages {
+		if strings.ToLower(key) == ext {
+			return normalize(ext, Language{
+				Name:           l.Name,
+				Compile:        l.Compile,
+				Run:            l.Run,
+				Source:         l.Source,
+				TimeMultiplier: l.TimeMultiplier,
+			}), true
+		}
+	}
+	lang, ok := builtin[ext]
+	return normalize(ext, lang), ok
+}
+
+// Languages 返回内置与配置文件中的全部语言，按扩展名排序
+func Languages() []Language {
+	exts := make(map[string]bool)
+	for ext := range builtin {
+		exts[ext] = true
+	}
+	for ext := range config.GetConfig().Languages {
+		exts[strings.ToLower(ext)] = true
+	}
+	languages := make([]Language, 0, len(exts))
+	for _, ext := range slices.Sorted(maps.Keys(exts)) {
+		lang, _ := LookupLanguage(ext)
+		languages = append(languages, lang)
+	}
+	return languages
+}
+
+// LanguageName 返回代码文件对应的语言名，未配置时为扩展名
+func LanguageName(codePath string) string {
+	lang, _ := LookupLanguage(filepath.Ext(codePath))
+	return lang.Name
+}
+
+func normalize(ext string, lang Language) Language {
+	lang.Ext = ext
+	if lang.Name == "" {
+		lang.Name = ext
+	}
+	if lang.TimeMultiplier <= 0 {
+		lang.TimeMultiplier = 1
+	}
+	return lang
+}
+
+// Limit 按时间倍数放宽时间限制
+func (l Language) Limit(d time.Duration) time.Duration {
+	return time.Duration(float64(d) * l.TimeMultiplier)
+}
+
+// Tools 返回编译与运行命令所需的外部程序，不含编译产物
+func (l Language) Tools() []string {
+	var tools []string
+	for _, tmpl := range []string{l.Compile, l.Run} {
+		fields := strings.Fields(tmpl)
+		if len(fields) > 0 && !strings.Contains(fields[0], "{") && !slices.Contains(tools, fields[0]) {
+			tools = append(tools, fields[0])
+		}
+	}
+	return tools
+}
+
+// CompileError 编译失败，Output 为编译器输出
+type CompileError struct {
+	Output string
+	Err    error
+}
+
+func (e *CompileError) Error() string {
+	return fmt.Sprintf("compile failed: %v", e.Err)
+}
+
+func (e *CompileError) Unwrap() error {
+	return e.Err
+}
+
+// Program 编译完成、可重复运行的程序，使用完需调用 Close 删除工作目录
+type Program struct {
+	dir string
+	run []string
+}
+
+// Compile 将源文件复制到临时目录并编译
+func Compile(ctx context.Context, src string, lang Language) (*Program, error) {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return nil, fmt.Errorf("read code file: %w", err)
+	}
+	dir, err := os.MkdirTemp("", "algo-run-")
+	if err != nil {
+		return nil, err
+	}
+	name := lang.Source
+	if name == "" {
+		name = filepath.Base(src)
+	}
+	vars := map[string]string{
+		"{src}":  filepath.Join(dir, name),
+		"{bin}":  filepath.Join(dir, "main"),
+		"{dir}":  dir,
+		"{name}": strings.TrimSuffix(name, filepath.Ext(name)),
+	}
+	if err = os.WriteFile(vars["{src}"], data, 0644); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	p := &Program{dir: dir, run: expand(lang.Run, vars)}
+	if len(p.run) == 0 {
+		p.Close()
+		return nil, errors.New("empty run command")
+	}
+	if args := expand(lang.Compile, vars); len(args) > 0 {
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			p.Close()
+			return nil, &CompileError{Output: string(out), Err: err}
+		}
+	}
+	return p, nil
+}
+
+// expand 按空白拆分命令模板并替换占位符
+func expand(tmpl string, vars map[string]string) []string {
+	args := strings.Fields(tmpl)
+	for i, arg := range args {
+		for k, v := range vars {
+			arg = strings.ReplaceAll(arg, k, v)
+		}
+		args[i] = arg
+	}
+	return args
+}
+
+// Close 删除工作目录
+func (p *Program) Close() error {
+	return os.RemoveAll(p.dir)
+}
+
+// Limits 运行限制，零值表示不限制
+type Limits struct {
+	Time   time.Duration // CPU 时间，墙钟时间超过其两倍时同样终止
+	Memory int64         // 峰值常驻内存，字节
+}
+
+// maxOutput 标准输出与标准错误各自最多保留的字节数
+const maxOutput = 64 << 20
+
+// Result 单个测试用例的运行结果
+type Result struct {
+	Verdict Verdict
+	Output  string
+	Stderr  string
+	Time    time.Duration // 墙钟时间
+	CPUTime time.Duration
+	Memory  int64  // 峰值常驻内存，字节，不支持的平台为 0
+	Err     error  // 运行错误或检查程序出错的原因
+	Message string // 检查程序给出的说明
+}
+
+// Run 以 input 作为标准输入运行程序，args 追加到运行命令之后，不比较输出。
+// 标准输入输出使用工作目录中的文件，进程退出后即可终止仍在运行的子进程
+func (p *Program) Run(ctx context.Context, input string, limits Limits, args ...string) *Result {
+	ctx, cancel := withDeadline(ctx, limits)
+	defer cancel()
+	files, err := p.openFiles(input)
+	if err != nil {
+		return &Result{Verdict: RE, Err: err}
+	}
+	defer files.close()
+
+	cmd := p.command(ctx, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = files.stdin, files.stdout, files.stderr
+	result := startProcess(ctx, cmd, limits).wait()
+	result.Output, result.Stderr = files.read()
+	return result
+}
+
+// withDeadline 墙钟时间超过 CPU 时间限制的两倍时取消
+func withDeadline(ctx context.Context, limits Limits) (context.Context, context.CancelFunc) {
+	if limits.Time > 0 {
+		return context.WithTimeout(ctx, 2*limits.Time)
+	}
+	return context.WithCancel(ctx)
+}
+
+func (p *Program) command(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, p.run[0], append(p.run[1:len(p.run):len(p.run)], args...)...)
+	cmd.Dir = p.dir
+	sandbox(cmd)
+	return cmd
+}
+
+// process 已启动的受限进程
+type process struct {
+	ctx    context.Context
+	cmd    *exec.Cmd
+	limits Limits
+	start  time.Time
+	peak   func() int64
+	err    error
+}
+
+func startProcess(ctx context.Context, cmd *exec.Cmd, limits Limits) *process {
+	pr := &process{ctx: ctx, cmd: cmd, limits: limits, start: time.Now()}
+	if pr.err = cmd.Start(); pr.err == nil {
+		pr.peak = watchMemory(cmd.Process.Pid)
+		if pr.err = setLimits(cmd.Process.Pid, limits); pr.err != nil {
+			_ = cmd.Cancel()
+		}
+	}
+	return pr
+}
+
+// wait 等待进程退出并终止其进程组，按资源占用判定结果，不读取输出
+func (pr *process) wait() *Result {
+	result := &Result{}
+	err := pr.err
+	if pr.cmd.Process != nil {
+		if waitErr := pr.cmd.Wait(); err == nil {
+			err = waitErr
+		}
+		cleanup(pr.cmd)
+		result.Memory = pr.peak()
+	}
+	result.Time, result.Err = time.Since(pr.start), err
+	state := pr.cmd.ProcessState
+	if state != nil {
+		result.CPUTime = state.UserTime() + state.SystemTime()
+	}
+	limits := pr.limits
+	switch {
+	case limits.Memory > 0 && result.Memory > limits.Memory:
+		result.Verdict = MLE
+	case pr.ctx.Err() == context.DeadlineExceeded,
+		limits.Time > 0 && result.CPUTime > limits.Time,
+		state != nil && cpuExceeded(state):
+		result.Verdict = TLE
+	case state != nil && outputExceeded(state):
+		result.Verdict, result.Err = RE, errors.New("output limit exceeded")
+	case err != nil:
+		result.Verdict = RE
+	default:
+		result.Verdict = AC
+	}
+	return result
+}
+
+// runFiles 一次运行使用的标准输入输出文件
+type runFiles struct {
+	stdin, stdout, stderr *os.File
+}
+
+func (p *Program) openFiles(input string) (*runFiles, error) {
+	f := &runFiles{}
+	var err error
+	if f.stdin, err = os.CreateTemp(p.dir, "stdin-"); err != nil {
+		return nil, err
+	}
+	if f.stdout, err = os.CreateTemp(p.dir, "stdout-"); err == nil {
+		f.stderr, err = os.CreateTemp(p.dir, "stderr-")
+	}
+	if err == nil {
+		_, err = f.stdin.WriteString(input)
+	}
+	if err == nil {
+		_, err = f.stdin.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		f.close()
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *runFiles) read() (string, string) {
+	return readOutput(f.stdout), readOutput(f.stderr)
+}
+
+// readOutput 从头读取程序写入的输出文件，最多 maxOutput 字节
+func readOutput(file *os.File) string {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return ""
+	}
+	data, _ := io.ReadAll(io.LimitReader(file, maxOutput))
+	return string(data)
+}
+
+func (f *runFiles) close() {
+	for _, file := range []*os.File{f.stdin, f.stdout, f.stderr} {
+		if file != nil {
+			file.Close()
+			os.Remove(file.Name())
+		}
+	}
+}
+
+// Test 运行程序并用 checker 检查输出，checker 为 nil 时使用 ExactChecker
+func (p *Program) Test(ctx context.Context, input, expected string, limits Limits, checker Checker) *Result {
+	result := p.Run(ctx, input, limits)
+	if result.Verdict != AC {
+		return result
+	}
+	if checker == nil {
+		checker = ExactChecker{}
+	}
+	ok, message, err := checker.Check(ctx, input, expected, result.Output)
+	result.Message = message
+	switch {
+	case err != nil:
+		result.Verdict, result.Err = FAIL, err
+	case !ok:
+		result.Verdict = WA
+	}
+	return result
+}
+
+// Check 忽略行尾空白与末尾空行比较输出
+func Check(expected, actual string) bool {
+	return slices.Equal(lines(expected), lines(actual))
+}
+
+func lines(s string) []string {
+	parts := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i, line := range parts {
+		parts[i] = strings.TrimRight(line, " \t")
+	}
+	for len(parts) > 0 && parts[len(parts)-1] == "" {
+		parts = parts[:len(parts)-1]
+	}
+	return parts
+}
+
+// maxDiffLines Diff 最多列出的不同行数
+const maxDiffLines = 10
+
+// Diff 逐行对比期望输出与实际输出，只列出不同的行
+func Diff(expected, actual string) string {
+	want, got := lines(expected), lines(actual)
+	var b strings.Builder
+	shown := 0
+	for i := 0; i < max(len(want), len(got)); i++ {
+		var w, g string
+		if i < len(want) {
+			w = want[i]
+		}
+		if i < len(got) {
+			g = got[i]
+		}
+		if i < len(want) && i < len(got) && w == g {
+			continue
+		}
+		if shown++; shown > maxDiffLines {
+			b.WriteString("...\n")
+			break
+		}
+		fmt.Fprintf(&b, "line %d\n", i+1)
+		if i < len(want) {
+			fmt.Fprintf(&b, "- %s\n", w)
+		}
+		if i < len(got) {
+			fmt.Fprintf(&b, "+ %s\n", g)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,210 @@
+package runner
+
+import (
+	"algo/pkg/config"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// shell 解释执行的测试语言，避免测试依赖编译器
+var shell = Language{Ext: "sh", Name: "sh", Run: "sh {src}", TimeMultiplier: 1}
+
+// compileScript 将 script 写入临时文件并作为程序编译
+func compileScript(t *testing.T, script string) *Program {
+	t.Helper()
+	src := filepath.Join(t.TempDir(), "main.sh")
+	if err := os.WriteFile(src, []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := Compile(context.Background(), src, shell)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name             string
+		expected, actual string
+		want             bool
+	}{
+		{"equal", "1 2\n3\n", "1 2\n3\n", true},
+		{"trailing spaces", "1 2\n3\n", "1 2  \n3\t\n", true},
+		{"trailing blank lines", "1\n", "1\n\n\n", true},
+		{"missing final newline", "1\n2\n", "1\n2", true},
+		{"crlf", "1\n2\n", "1\r\n2\r\n", true},
+		{"leading spaces", "1\n", " 1\n", false},
+		{"inner spaces", "1 2\n", "1  2\n", false},
+		{"different line", "1\n2\n", "1\n3\n", false},
+		{"missing line", "1\n2\n", "1\n", false},
+		{"empty", "", "\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Check(tt.expected, tt.actual); got != tt.want {
+				t.Errorf("Check(%q, %q) = %v, want %v", tt.expected, tt.actual, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name             string
+		expected, actual string
+		want             string
+	}{
+		{"equal", "1\n2\n", "1\n2  \n", ""},
+		{"changed", "1\n2\n3\n", "1\n5\n3\n", "line 2\n- 2\n+ 5\n"},
+		{"missing", "1\n2\n", "1\n", "line 2\n- 2\n"},
+		{"extra", "1\n", "1\n2\n", "line 2\n+ 2\n"},
+		{"empty line", "1\n\n2\n", "1\n2\n", "line 2\n- \n+ 2\nline 3\n- 2\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.expected, tt.actual); got != tt.want {
+				t.Errorf("Diff(%q, %q) = %q, want %q", tt.expected, tt.actual, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffTruncated(t *testing.T) {
+	var want, got string
+	for i := 0; i < maxDiffLines+5; i++ {
+		want += "a\n"
+		got += "b\n"
+	}
+	diff := Diff(want, got)
+	if n := strings.Count(diff, "\n"); n != maxDiffLines*3+1 {
+		t.Errorf("Diff() has %d lines, want %d", n, maxDiffLines*3+1)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	vars := map[string]string{"{src}": "/tmp/w/main.cpp", "{bin}": "/tmp/w/main", "{dir}": "/tmp/w", "{name}": "main"}
+	tests := []struct {
+		tmpl string
+		want []string
+	}{
+		{"g++ -O2 -std=c++17 {src} -o {bin}", []string{"g++", "-O2", "-std=c++17", "/tmp/w/main.cpp", "-o", "/tmp/w/main"}},
+		{"java -cp {dir} {name}", []string{"java", "-cp", "/tmp/w", "main"}},
+		{"  {bin}  ", []string{"/tmp/w/main"}},
+		{"-I{dir}/include", []string{"-I/tmp/w/include"}},
+		{"echo {unknown}", []string{"echo", "{unknown}"}},
+		{"", []string{}},
+	}
+	for _, tt := range tests {
+		if got := expand(tt.tmpl, vars); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("expand(%q) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+}
+
+func TestCompileError(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "main.sh")
+	if err := os.WriteFile(src, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	lang := shell
+	lang.Compile = "sh -c {name}-does-not-exist"
+	_, err := Compile(context.Background(), src, lang)
+	var compileErr *CompileError
+	if !errors.As(err, &compileErr) {
+		t.Errorf("Compile() error = %v, want *CompileError", err)
+	}
+}
+
+func TestVerdicts(t *testing.T) {
+	limits := Limits{Time: 300 * time.Millisecond}
+	tests := []struct {
+		name   string
+		script string
+		want   Verdict
+	}{
+		{"accepted", "read a b; echo $((a + b))", AC},
+		{"wrong answer", "read a b; echo $((a - b))", WA},
+		{"runtime error", "echo 3; exit 3", RE},
+		{"time limit", "while :; do :; done", TLE},
+		{"sleeping", "sleep 5; echo 3", TLE},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := compileScript(t, tt.script)
+			result := p.Test(context.Background(), "1 2\n", "3\n", limits, nil)
+			if result.Verdict != tt.want {
+				t.Errorf("Test() verdict = %s, want %s (err %v)", result.Verdict, tt.want, result.Err)
+			}
+			if result.Verdict == TLE && result.Time > 5*time.Second {
+				t.Errorf("Test() ran %v, want the program killed after the wall limit", result.Time)
+			}
+		})
+	}
+}
+
+func TestRunOutput(t *testing.T) {
+	p := compileScript(t, `cat; echo "$@"; echo oops >&2`)
+	result := p.Run(context.Background(), "in\n", Limits{Time: time.Second}, "x", "y")
+	if result.Verdict != AC || result.Output != "in\nx y\n" || result.Stderr != "oops\n" {
+		t.Errorf("Run() = %+v", result)
+	}
+}
+
+func TestLookupLanguage(t *testing.T) {
+	cnf := config.GetConfig()
+	saved := cnf.Languages
+	t.Cleanup(func() { cnf.Languages = saved })
+	cnf.Languages = map[string]config.Language{
+		"PY":  {Run: "pypy3 {src}", TimeMultiplier: 2},
+		"kt":  {Name: "kotlin", Compile: "kotlinc {src} -d {dir}", Run: "kotlin -cp {dir} MainKt"},
+		"cpp": {Compile: "clang++ {src} -o {bin}", Run: "{bin}"},
+	}
+
+	tests := []struct {
+		ext  string
+		ok   bool
+		want Language
+	}{
+		{".go", true, Language{Ext: "go", Name: "go", Compile: "go build -o {bin} {src}", Run: "{bin}", TimeMultiplier: 1}},
+		{"JAVA", true, Language{Ext: "java", Name: "java", Compile: "javac -d {dir} {src}", Run: "java -cp {dir} Main", Source: "Main.java", TimeMultiplier: 2}},
+		{"py", true, Language{Ext: "py", Name: "py", Run: "pypy3 {src}", TimeMultiplier: 2}},
+		{"kt", true, Language{Ext: "kt", Name: "kotlin", Compile: "kotlinc {src} -d {dir}", Run: "kotlin -cp {dir} MainKt", TimeMultiplier: 1}},
+		{"cpp", true, Language{Ext: "cpp", Name: "cpp", Compile: "clang++ {src} -o {bin}", Run: "{bin}", TimeMultiplier: 1}},
+		{".txt", false, Language{Ext: "txt", Name: "txt", TimeMultiplier: 1}},
+	}
+	for _, tt := range tests {
+		got, ok := LookupLanguage(tt.ext)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("LookupLanguage(%q) = %+v, %v, want %+v, %v", tt.ext, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if got := LanguageName("/code/0001_two-sum.rs"); got != "rust" {
+		t.Errorf("LanguageName() = %q, want rust", got)
+	}
+	if got := LanguageName(""); got != "" {
+		t.Errorf("LanguageName(\"\") = %q, want empty", got)
+	}
+}
+
+func TestLanguageLimitAndTools(t *testing.T) {
+	lang := Language{Compile: "g++ {src} -o {bin}", Run: "{bin}", TimeMultiplier: 2.5}
+	if got := lang.Limit(2 * time.Second); got != 5*time.Second {
+		t.Errorf("Limit() = %v, want 5s", got)
+	}
+	java := Language{Compile: "javac -d {dir} {src}", Run: "java -cp {dir} Main"}
+	if got := java.Tools(); len(got) != 2 || got[0] != "javac" || got[1] != "java" {
+		t.Errorf("Tools() = %v, want [javac java]", got)
+	}
+	if got := lang.Tools(); len(got) != 1 || got[0] != "g++" {
+		t.Errorf("Tools() = %v, want [g++]", got)
+	}
+}
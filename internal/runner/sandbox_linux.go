@@ -0,0 +1,119 @@
+//go:build linux
+
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// sandbox 让程序在独立的进程组中运行，超时或结束时终止整个进程组
+func sandbox(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+// setLimits 通过 prlimit 限制 CPU 时间与可写内存。进程启动后才能设置，
+// 启动瞬间的资源占用不受限制；RLIMIT_DATA 不计入只保留未使用的地址空间，Go、Java 等运行时也能正常启动
+func setLimits(pid int, limits Limits) error {
+	if limits.Time > 0 {
+		seconds := uint64((limits.Time+time.Second-1)/time.Second) + 1
+		if err := prlimit(pid, syscall.RLIMIT_CPU, seconds); err != nil {
+			return err
+		}
+	}
+	if limits.Memory > 0 {
+		// 留出余量，超出限制的程序仍能运行完，再按峰值内存判定 MLE
+		if err := prlimit(pid, syscall.RLIMIT_DATA, uint64(limits.Memory)*2); err != nil {
+			return err
+		}
+	}
+	// 输出写入文件，超出后进程收到 SIGXFSZ
+	return prlimit(pid, syscall.RLIMIT_FSIZE, maxOutput)
+}
+
+func prlimit(pid, resource int, value uint64) error {
+	limit := syscall.Rlimit{Cur: value, Max: value}
+	_, _, errno := syscall.RawSyscall6(syscall.SYS_PRLIMIT64, uintptr(pid), uintptr(resource),
+		uintptr(unsafe.Pointer(&limit)), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// cleanup 终止进程组中仍在运行的后台子进程
+func cleanup(cmd *exec.Cmd) {
+	if cmd.Process != nil {
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+// watchMemory 定时读取 /proc/<pid>/status 中的 VmHWM，返回的函数停止读取并给出峰值常驻内存（字节）。
+// rusage 的 Maxrss 会包含 exec 之前父进程的内存，不能用来判断 MLE
+func watchMemory(pid int) func() int64 {
+	var peak atomic.Int64
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	path := "/proc/" + strconv.Itoa(pid) + "/status"
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(5 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			if hwm := readHWM(path); hwm > peak.Load() {
+				peak.Store(hwm)
+			}
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() int64 {
+		close(done)
+		<-stopped
+		return peak.Load()
+	}
+}
+
+func readHWM(path string) int64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if value, ok := bytes.CutPrefix(scanner.Bytes(), []byte("VmHWM:")); ok {
+			kb, _ := strconv.ParseInt(string(bytes.TrimSpace(bytes.TrimSuffix(bytes.TrimSpace(value), []byte("kB")))), 10, 64)
+			return kb << 10
+		}
+	}
+	return 0
+}
+
+// signaled 进程是否被 sig 终止
+func signaled(state *os.ProcessState, sig syscall.Signal) bool {
+	status, ok := state.Sys().(syscall.WaitStatus)
+	return ok && status.Signaled() && status.Signal() == sig
+}
+
+// cpuExceeded 进程是否因 RLIMIT_CPU 被终止
+func cpuExceeded(state *os.ProcessState) bool {
+	return signaled(state, syscall.SIGXCPU)
+}
+
+// outputExceeded 进程是否因 RLIMIT_FSIZE 被终止
+func outputExceeded(state *os.ProcessState) bool {
+	return signaled(state, syscall.SIGXFSZ)
+}
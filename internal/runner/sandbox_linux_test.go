@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryLimit(t *testing.T) {
+	// 约 64MB 的字符串常驻在 shell 进程中
+	p := compileScript(t, `x=$(head -c 67108864 /dev/zero | tr '\0' a); echo ${#x}`)
+	result := p.Run(context.Background(), "", Limits{Time: 10 * time.Second, Memory: 16 << 20})
+	if result.Verdict != MLE {
+		t.Errorf("Run() verdict = %s, want MLE (memory %d, err %v)", result.Verdict, result.Memory, result.Err)
+	}
+	if result.Memory <= 16<<20 {
+		t.Errorf("Run() memory = %d, want above the 16MB limit", result.Memory)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	p := compileScript(t, `i=0; while [ $i -lt 200000 ]; do i=$((i + 1)); done; echo $i`)
+	result := p.Run(context.Background(), "", Limits{Time: 10 * time.Second, Memory: 256 << 20})
+	if result.Verdict != AC || result.Output != "200000\n" {
+		t.Fatalf("Run() = %s %q, err %v", result.Verdict, result.Output, result.Err)
+	}
+	if result.CPUTime <= 0 || result.Memory <= 0 {
+		t.Errorf("Run() cpu %v, memory %d, want both recorded", result.CPUTime, result.Memory)
+	}
+}
+
+func TestKillProcessGroup(t *testing.T) {
+	// 后台子进程继承了输出文件，进程退出后必须一并终止，否则会一直占用
+	p := compileScript(t, `sleep 30 & echo started`)
+	start := time.Now()
+	result := p.Run(context.Background(), "", Limits{Time: time.Second})
+	if result.Verdict != AC || result.Output != "started\n" {
+		t.Errorf("Run() = %s %q, err %v", result.Verdict, result.Output, result.Err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run() took %v, want the background child killed", elapsed)
+	}
+
+	p = compileScript(t, `sleep 30 & wait`)
+	start = time.Now()
+	result = p.Run(context.Background(), "", Limits{Time: 200 * time.Millisecond})
+	if result.Verdict != TLE {
+		t.Errorf("Run() verdict = %s, want TLE", result.Verdict)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run() took %v, want the process group killed at the wall limit", elapsed)
+	}
+}
+
+func TestOutputLimit(t *testing.T) {
+	// exec 让写输出的进程就是被限制的进程，收到 SIGXFSZ 的不是子进程
+	p := compileScript(t, `exec head -c 100000000 /dev/zero`)
+	result := p.Run(context.Background(), "", Limits{Time: 10 * time.Second})
+	if result.Verdict != RE || result.Err == nil || result.Err.Error() != "output limit exceeded" {
+		t.Errorf("Run() = %s, %v, want RE with output limit exceeded", result.Verdict, result.Err)
+	}
+	if len(result.Output) > maxOutput {
+		t.Errorf("Run() kept %d bytes of output, want at most %d", len(result.Output), maxOutput)
+	}
+}
@@ -0,0 +1,30 @@
+//go:build !linux
+
+package runner
+
+import (
+	"os"
+	"os/exec"
+)
+
+// 非 Linux 平台不限制资源，只在超时时终止进程
+
+func sandbox(cmd *exec.Cmd) {}
+
+func setLimits(pid int, limits Limits) error {
+	return nil
+}
+
+func cleanup(cmd *exec.Cmd) {}
+
+func watchMemory(pid int) func() int64 {
+	return func() int64 { return 0 }
+}
+
+func cpuExceeded(state *os.ProcessState) bool {
+	return false
+}
+
+func outputExceeded(state *os.ProcessState) bool {
+	return false
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"algo/internal/model"
+	"algo/internal/runner"
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// AttemptInput 记录一次做题尝试的参数
+type AttemptInput struct {
+	Verdict  string        `json:"verdict"` // AC|WA|TLE|MLE|RE|CE
+	Duration time.Duration `json:"duration"`
+	Language string        `json:"language"` // 为空时按代码文件推断
+	CodePath string        `json:"codePath"` // 保存为快照的代码文件，为空时使用题目代码
+	Note     string        `json:"note"`
+}
+
+// AddAttempt 记录一次做题尝试，保存当时的代码快照并更新题目状态
+func (s *Service) AddAttempt(ctx context.Context, slug string, in *AttemptInput) (*model.Attempt, error) {
+	verdict, ok := model.ValidVerdict(in.Verdict)
+	if !ok {
+		return nil, invalid("verdict", "must be one of %s", strings.Join(model.AttemptVerdicts, "|"))
+	}
+	if in.Duration < 0 {
+		return nil, invalid("duration", "must not be negative")
+	}
+	attempt := &model.Attempt{
+		Verdict:   verdict,
+		Duration:  int64(in.Duration / time.Second),
+		Language:  strings.TrimSpace(in.Language),
+		Note:      in.Note,
+		CreatedAt: time.Now(),
+	}
+	// 事务失败时删除本次写入的代码文件
+	var written []string
+	err := s.transaction(ctx, func(tx *gorm.DB) error {
+		var problem model.Problem
+		if err := tx.Where("slug = ?", slug).First(&problem).Error; err != nil {
+			return notFound(err, "problem %s", slug)
+		}
+		attempt.ProblemID = problem.ID
+		src := in.CodePath
+		if src == "" {
+			src = problem.CodePath
+		} else if problem.CodePath == "" {
+			// 待做的题目没有代码时，将本次代码保存为题目代码
+			// 目标文件原本就存在时会被覆盖，失败时不能删除
+			_, statErr := os.Stat(problem.CodeFile(filepath.Ext(src)))
+			problem.CodePath = src
+			if err := problem.CopyCode(); err != nil {
+				return err
+			}
+			if os.IsNotExist(statErr) {
+				written = append(written, problem.CodePath)
+			}
+			if err := tx.Model(&problem).Update("code_path", problem.CodePath).Error; err != nil {
+				return fmt.Errorf("failed to save code: %w", err)
+			}
+		}
+		if src != "" {
+			snapshot, err := problem.SnapshotCode(src, attempt.CreatedAt)
+			if err != nil {
+				return err
+			}
+			attempt.CodePath = snapshot
+			written = append(written, snapshot)
+		}
+		if attempt.Language == "" {
+			attempt.Language = runner.LanguageName(src)
+		}
+		attempt.ID = nextID(tx, &model.Attempt{})
+		if err := tx.Create(attempt).Error; err != nil {
+			return fmt.Errorf("failed to save attempt: %w", err)
+		}
+		if status := problem.Status.AfterAttempt(verdict); status != problem.Status {
+			problem.SetStatus(status, attempt.CreatedAt)
+			if err := tx.Model(&problem).Select("status", "solved_at").Updates(&problem).Error; err != nil {
+				return fmt.Errorf("failed to update status: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		for _, path := range written {
+			_ = os.Remove(path)
+		}
+		return nil, err
+	}
+	return attempt, nil
+}
+
+// ListAttempts 按时间顺序查询题目的全部尝试
+func (s *Service) ListAttempts(ctx context.Context, slug string) ([]*model.Attempt, error) {
+	problem, err := s.GetProblem(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return problem.Attempts, nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"algo/internal/model"
+	"algo/internal/runner"
+	"algo/pkg/config"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAddAttempt(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	problem := addProblem(t, svc, &AddInput{Title: "A", Status: string(model.StatusTodo)})
+
+	code := writeFile(t, "main.py", "print(1)\n")
+	first, err := svc.AddAttempt(ctx, problem.Slug, &AttemptInput{Verdict: "wa", Duration: 90 * time.Second, CodePath: code})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := svc.AddAttempt(ctx, problem.Slug, &AttemptInput{Verdict: "AC", Note: "off by one"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ID != 1 || second.ID != 2 {
+		t.Errorf("attempt IDs = %d, %d, want 1, 2", first.ID, second.ID)
+	}
+	if first.Verdict != "WA" || first.Duration != 90 || first.Language != runner.LanguageName(code) {
+		t.Errorf("first attempt = %+v", first)
+	}
+	// 同一秒内的两次快照不能相互覆盖
+	if first.CodePath == "" || first.CodePath == second.CodePath {
+		t.Errorf("snapshots = %q, %q, want two different files", first.CodePath, second.CodePath)
+	}
+
+	got, err := svc.GetProblem(ctx, problem.Slug)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != model.StatusSolved || got.CodePath == "" || len(got.Attempts) != 2 {
+		t.Errorf("problem after attempts = status %s, code %q, %d attempts", got.Status, got.CodePath, len(got.Attempts))
+	}
+
+	if _, err = svc.AddAttempt(ctx, problem.Slug, &AttemptInput{Verdict: "OK"}); err == nil {
+		t.Error("AddAttempt() accepted verdict OK")
+	}
+}
+
+func TestAddAttemptCleanup(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	problem := addProblem(t, svc, &AddInput{Title: "A", Status: string(model.StatusTodo)})
+
+	// 保存尝试失败时，不能留下复制的代码与快照
+	if err := svc.db.Migrator().DropTable(&model.Attempt{}); err != nil {
+		t.Fatal(err)
+	}
+	code := writeFile(t, "main.cpp", "int main() {}\n")
+	if _, err := svc.AddAttempt(ctx, problem.Slug, &AttemptInput{Verdict: "AC", CodePath: code}); err == nil {
+		t.Fatal("AddAttempt() succeeded without the attempts table")
+	}
+	entries, err := os.ReadDir(config.GetConfig().Dir.CodeDir)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		t.Errorf("failed attempt left %s", e.Name())
+	}
+}
+
+func TestAddAttemptKeepsExistingCode(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	problem := addProblem(t, svc, &AddInput{Title: "A", Status: string(model.StatusTodo)})
+
+	// 目标位置原本就有的文件不属于本次尝试，失败时不能删除
+	dst := problem.CodeFile(".cpp")
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dst, []byte("// old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.db.Migrator().DropTable(&model.Attempt{}); err != nil {
+		t.Fatal(err)
+	}
+	code := writeFile(t, "main.cpp", "int main() {}\n")
+	if _, err := svc.AddAttempt(ctx, problem.Slug, &AttemptInput{Verdict: "AC", CodePath: code}); err == nil {
+		t.Fatal("AddAttempt() succeeded without the attempts table")
+	}
+	if _, err := os.Stat(dst); err != nil {
+		t.Errorf("failed attempt removed the existing code file: %v", err)
+	}
+}
+
+func TestSolvedAt(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	solved := addProblem(t, svc, &AddInput{Title: "Solved"})
+	if solved.SolvedAt == nil {
+		t.Error("AddProblem() did not record the solved time")
+	}
+	todo := addProblem(t, svc, &AddInput{Title: "Todo", Status: string(model.StatusTodo)})
+	if todo.SolvedAt != nil {
+		t.Errorf("todo problem solved at %v", todo.SolvedAt)
+	}
+
+	if _, err := svc.AddAttempt(ctx, todo.Slug, &AttemptInput{Verdict: "WA", CodePath: writeFile(t, "main.cpp", "int main() {}\n")}); err != nil {
+		t.Fatal(err)
+	}
+	accepted, err := svc.AddAttempt(ctx, todo.Slug, &AttemptInput{Verdict: "AC"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := svc.GetProblem(ctx, todo.Slug)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SolvedAt == nil || !got.SolvedAt.Equal(accepted.CreatedAt) {
+		t.Errorf("solved at %v, want the accepted attempt at %v", got.SolvedAt, accepted.CreatedAt)
+	}
+
+	// 再次标记为已解决时保留第一次的解决时间
+	first := *got.SolvedAt
+	for _, status := range []model.Status{model.StatusAttempted, model.StatusSolved} {
+		if got, err = svc.EditProblem(ctx, todo.Slug, &EditInput{Status: string(status)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got.SolvedAt == nil || !got.SolvedAt.Equal(first) {
+		t.Errorf("solved at after editing = %v, want %v", got.SolvedAt, first)
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"algo/internal/importer"
+	"algo/internal/model"
+	"algo/pkg/config"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CaptureInput Competitive Companion 推送的题目，字段与插件的 JSON 一致
+type CaptureInput struct {
+	Name        string      `json:"name"`
+	Group       string      `json:"group"` // 如 "Codeforces - Codeforces Round 911 (Div. 2)"
+	URL         string      `json:"url"`
+	TimeLimit   int         `json:"timeLimit"`
+	MemoryLimit int         `json:"memoryLimit"`
+	Tests       []TestInput `json:"tests"`
+}
+
+// CaptureOptions 捕获题目时使用的语言与难度，插件不提供难度
+type CaptureOptions struct {
+	Language   string
+	Difficulty string
+}
+
+// 没有 code.<语言> 模板时使用的代码骨架
+var scaffolds = map[string]string{
+	"cpp":  "#include <bits/stdc++.h>\nusing namespace std;\n\nint main() {\n    ios::sync_with_stdio(false);\n    cin.tie(nullptr);\n\n    return 0;\n}\n",
+	"c":    "#include <stdio.h>\n\nint main(void) {\n\n    return 0;\n}\n",
+	"go":   "package main\n\nimport (\n\t\"bufio\"\n\t\"os\"\n)\n\nfunc main() {\n\tin := bufio.NewReader(os.Stdin)\n\tout := bufio.NewWriter(os.Stdout)\n\tdefer out.Flush()\n\t_ = in\n}\n",
+	"py":   "import sys\n\ninput = sys.stdin.readline\n\n\ndef main():\n    pass\n\n\nmain()\n",
+	"java": "import java.util.*;\nimport java.io.*;\n\npublic class Main {\n    public static void main(String[] args) throws IOException {\n    }\n}\n",
+}
+
+// Scaffold 返回新题目的代码骨架，优先使用 TemplateDir 下的 code.<语言>
+func Scaffold(language string) (string, error) {
+	language = strings.TrimPrefix(strings.TrimSpace(language), ".")
+	path := filepath.Join(config.GetConfig().Dir.TemplateDir, "code."+language)
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return string(data), nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	if code, ok := scaffolds[language]; ok {
+		return code, nil
+	}
+	return "\n", nil
+}
+
+// CaptureProblem 将插件推送的题目与样例保存为待做题目，并生成代码骨架，同一链接不会重复添加
+func (s *Service) CaptureProblem(ctx context.Context, in *CaptureInput, opts CaptureOptions) (*model.Problem, error) {
+	if strings.TrimSpace(in.URL) == "" {
+		return nil, invalid("url", "is required")
+	}
+	var count int64
+	s.db.WithContext(ctx).Model(&model.Problem{}).Where("solution_url = ?", in.URL).Count(&count)
+	if count > 0 {
+		return nil, conflict("problem %s", in.URL)
+	}
+	code, err := Scaffold(opts.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	add := &AddInput{
+		Title:       in.Name,
+		Difficulty:  opts.Difficulty,
+		SolutionURL: in.URL,
+		Code:        code,
+		Language:    opts.Language,
+		TimeLimit:   in.TimeLimit,
+		MemoryLimit: in.MemoryLimit,
+		Tests:       in.Tests,
+		Status:      string(model.StatusTodo),
+	}
+	// 分组为 "<题库> - <比赛>" 且链接属于已注册题库时关联比赛
+	if _, contest, ok := strings.Cut(in.Group, " - "); ok {
+		if p, err := importer.ForURL(in.URL); err == nil {
+			add.Contest = strings.TrimSpace(contest)
+			add.ContestType = p.Name()
+		}
+	}
+	return s.AddProblem(ctx, add)
+}
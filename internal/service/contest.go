@@ -0,0 +1,150 @@
+package service
+
+import (
+	"algo/internal/importer"
+	"algo/internal/model"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"strings"
+)
+
+// checkContestType 按已注册的在线题库校验竞赛类型，简写会被转换为完整名称
+func checkContestType(contestType string) (model.ContestType, error) {
+	p, ok := importer.Lookup(contestType)
+	if !ok {
+		return "", invalid("contest type", "must be one of %s", strings.Join(importer.Names(), "|"))
+	}
+	return model.ContestType(p.Name()), nil
+}
+
+// ContestTypes 返回全部合法的竞赛类型
+func ContestTypes() []string {
+	return importer.Names()
+}
+
+// attachContest 将题目加入竞赛，竞赛不存在时按类型新建
+func attachContest(tx *gorm.DB, problem *model.Problem, contest, contestType string) error {
+	cts := &model.Contest{}
+	if err := tx.Where("title = ?", contest).Preload("Problems").First(cts).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		ct, err := checkContestType(contestType)
+		if err != nil {
+			return err
+		}
+		cts = &model.Contest{
+			ID:       nextID(tx, &model.Contest{}),
+			Title:    contest,
+			Type:     ct,
+			Problems: []*model.Problem{},
+		}
+	}
+
+	cts.Problems = append(cts.Problems, problem)
+	return tx.Save(cts).Error
+}
+
+// ListContests 查询全部竞赛，不包含题目
+func (s *Service) ListContests(ctx context.Context) ([]*model.Contest, error) {
+	var contests []*model.Contest
+	if err := s.db.WithContext(ctx).Order("id").Find(&contests).Error; err != nil {
+		return nil, err
+	}
+	return contests, nil
+}
+
+// GetContest 按 ID 查询竞赛，预加载题目及其标签
+func (s *Service) GetContest(ctx context.Context, id int64) (*model.Contest, error) {
+	var contest model.Contest
+	if err := s.db.WithContext(ctx).Preload("Problems.Tags").Preload("Problems.Attempts").First(&contest, id).Error; err != nil {
+		return nil, notFound(err, "contest %d", id)
+	}
+	return &contest, nil
+}
+
+// FindContest 按标题与类型查询竞赛，预加载题目及其标签
+func (s *Service) FindContest(ctx context.Context, title, contestType string) (*model.Contest, error) {
+	if p, ok := importer.Lookup(contestType); ok {
+		contestType = p.Name()
+	}
+	var contest model.Contest
+	err := s.db.WithContext(ctx).Where("title = ? and `type` = ?", title, contestType).
+		Preload("Problems.Tags").Preload("Problems.Attempts").First(&contest).Error
+	if err != nil {
+		return nil, notFound(err, "contest %s", title)
+	}
+	return &contest, nil
+}
+
+// CreateContest 新建竞赛，标题不能重复
+func (s *Service) CreateContest(ctx context.Context, title, contestType string) (*model.Contest, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, invalid("title", "is required")
+	}
+	ct, err := checkContestType(contestType)
+	if err != nil {
+		return nil, err
+	}
+	contest := &model.Contest{Title: title, Type: ct}
+	err = s.transaction(ctx, func(tx *gorm.DB) error {
+		var count int64
+		tx.Model(&model.Contest{}).Where("title = ?", title).Count(&count)
+		if count > 0 {
+			return conflict("contest %s", title)
+		}
+		contest.ID = nextID(tx, &model.Contest{})
+		return tx.Create(contest).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return contest, nil
+}
+
+// UpdateContest 修改竞赛标题或类型，空字符串表示不修改
+func (s *Service) UpdateContest(ctx context.Context, id int64, title, contestType string) (*model.Contest, error) {
+	var contest model.Contest
+	err := s.transaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.First(&contest, id).Error; err != nil {
+			return notFound(err, "contest %d", id)
+		}
+		if title = strings.TrimSpace(title); title != "" && title != contest.Title {
+			var count int64
+			tx.Model(&model.Contest{}).Where("title = ?", title).Count(&count)
+			if count > 0 {
+				return conflict("contest %s", title)
+			}
+			contest.Title = title
+		}
+		if contestType != "" {
+			ct, err := checkContestType(contestType)
+			if err != nil {
+				return err
+			}
+			contest.Type = ct
+		}
+		return tx.Save(&contest).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &contest, nil
+}
+
+// DeleteContest 删除竞赛，题目保留并解除关联
+func (s *Service) DeleteContest(ctx context.Context, id int64) error {
+	return s.transaction(ctx, func(tx *gorm.DB) error {
+		var contest model.Contest
+		if err := tx.First(&contest, id).Error; err != nil {
+			return notFound(err, "contest %d", id)
+		}
+		// sqlite 默认不启用外键，手动解除题目与竞赛的关联
+		if err := tx.Model(&model.Problem{}).Where("contest_id = ?", contest.ID).Update("contest_id", 0).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&contest).Error
+	})
+}
@@ -0,0 +1,569 @@
+package service
+
+import (
+	"algo/internal/generator"
+	"algo/internal/model"
+	"algo/internal/runner"
+	"algo/internal/stat"
+	"algo/pkg/config"
+	"context"
+	"fmt"
+	"github.com/flosch/pongo2"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GenerateOptions 批量生成的参数
+type GenerateOptions struct {
+	Templates map[generator.Kind]string // 本次使用的模板文件，覆盖配置
+	Force     bool                      // 忽略缓存全部重新生成
+	Jobs      int                       // 并发数，<= 0 时使用 CPU 数
+}
+
+// GenerateFailure 单个文档生成失败
+type GenerateFailure struct {
+	Path string
+	Err  error
+}
+
+// GenerateResult 批量生成的结果
+type GenerateResult struct {
+	Generated int
+	Unchanged int
+	Removed   []string
+	Failed    []*GenerateFailure
+}
+
+// templates 本次生成使用的各类模板内容
+type templates struct {
+	problem string
+	contest string
+	index   string
+	tag     string
+}
+
+// TemplateLoader 根据配置创建模板加载器，overrides 为一次性指定的模板文件
+func TemplateLoader(overrides map[generator.Kind]string) *generator.Loader {
+	cnf := config.GetConfig()
+	return &generator.Loader{
+		Dir: cnf.Dir.TemplateDir,
+		Files: map[generator.Kind]string{
+			generator.KindProblem: cnf.Template.Problem,
+			generator.KindContest: cnf.Template.Contest,
+			generator.KindIndex:   cnf.Template.Index,
+			generator.KindTag:     cnf.Template.Tag,
+		},
+		Overrides: overrides,
+	}
+}
+
+func loadTemplates(overrides map[generator.Kind]string) (*templates, error) {
+	loader := TemplateLoader(overrides)
+	tpls := &templates{}
+	for kind, dst := range map[generator.Kind]*string{
+		generator.KindProblem: &tpls.problem,
+		generator.KindContest: &tpls.contest,
+		generator.KindIndex:   &tpls.index,
+		generator.KindTag:     &tpls.tag,
+	} {
+		tpl, _, err := loader.Load(kind)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s template: %w", kind, err)
+		}
+		*dst = tpl
+	}
+	return tpls, nil
+}
+
+// GenerateProblem 生成单个题目文档，返回文件路径
+func (s *Service) GenerateProblem(ctx context.Context, slug string, overrides map[generator.Kind]string) (string, error) {
+	problem, err := s.GetProblem(ctx, slug)
+	if err != nil {
+		return "", err
+	}
+	return generateOne(overrides, &genJob{problem: problem})
+}
+
+// GenerateContest 生成单个竞赛文档，返回文件路径
+func (s *Service) GenerateContest(ctx context.Context, title, contestType string, overrides map[generator.Kind]string) (string, error) {
+	contest, err := s.FindContest(ctx, title, contestType)
+	if err != nil {
+		return "", err
+	}
+	return generateOne(overrides, &genJob{contest: contest})
+}
+
+func generateOne(overrides map[generator.Kind]string, job *genJob) (string, error) {
+	tpls, err := loadTemplates(overrides)
+	if err != nil {
+		return "", err
+	}
+	dir := config.GetConfig().Dir.MarkdownDir
+	manifest, err := generator.LoadManifest(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to load generate manifest: %w", err)
+	}
+	if _, err = job.run(manifest, tpls, true); err != nil {
+		return "", err
+	}
+	if err = manifest.Save(); err != nil {
+		return "", fmt.Errorf("failed to save generate manifest: %w", err)
+	}
+	return filepath.Join(dir, job.rel()), nil
+}
+
+// genJob 一个待生成的文档，problem、contest 与 tag 三选一
+type genJob struct {
+	problem *model.Problem
+	contest *model.Contest
+	tag     *generator.Group
+}
+
+func (j *genJob) rel() string {
+	switch {
+	case j.problem != nil:
+		return problemMarkdownPath(j.problem)
+	case j.contest != nil:
+		return contestMarkdownPath(j.contest)
+	default:
+		return tagMarkdownPath(j.tag.Name)
+	}
+}
+
+func (j *genJob) run(manifest *generator.Manifest, tpls *templates, force bool) (bool, error) {
+	switch {
+	case j.problem != nil:
+		return writeProblemMarkdown(manifest, tpls, j.problem, force)
+	case j.contest != nil:
+		return writeContestMarkdown(manifest, tpls, j.contest, force)
+	default:
+		return writeTagMarkdown(manifest, tpls, j.tag, force)
+	}
+}
+
+// Generate 并发生成已解决题目及其竞赛与标签的文档，跳过输入未变化的文件并删除已失效的文档
+func (s *Service) Generate(ctx context.Context, opts *GenerateOptions) (*GenerateResult, error) {
+	tpls, err := loadTemplates(opts.Templates)
+	if err != nil {
+		return nil, err
+	}
+	conn := s.db.WithContext(ctx)
+	var problems []*model.Problem
+	if err = conn.Preload("Tags").Preload("Attempts").Where("status IN ?", model.SolvedStatuses).Order("id").Find(&problems).Error; err != nil {
+		return nil, fmt.Errorf("failed to load problems: %w", err)
+	}
+	var contests []*model.Contest
+	if err = conn.Preload("Problems", "status IN ?", model.SolvedStatuses).Preload("Problems.Tags").Preload("Problems.Attempts").
+		Order("id").Find(&contests).Error; err != nil {
+		return nil, fmt.Errorf("failed to load contests: %w", err)
+	}
+	index, err := s.BuildIndex(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tags: %w", err)
+	}
+	manifest, err := generator.LoadManifest(config.GetConfig().Dir.MarkdownDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load generate manifest: %w", err)
+	}
+
+	queue := make([]*genJob, 0, len(problems)+len(contests)+len(index.Tags))
+	for _, p := range problems {
+		queue = append(queue, &genJob{problem: p})
+	}
+	for _, c := range contests {
+		// 没有已解决题目的竞赛不生成文档
+		if len(c.Problems) > 0 {
+			queue = append(queue, &genJob{contest: c})
+		}
+	}
+	for _, t := range index.Tags {
+		queue = append(queue, &genJob{tag: t})
+	}
+	keep := make(map[string]bool, len(queue))
+	for _, job := range queue {
+		keep[job.rel()] = true
+	}
+
+	jobs := opts.Jobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		result = &GenerateResult{}
+		ch     = make(chan *genJob)
+	)
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range ch {
+				written, err := job.run(manifest, tpls, opts.Force)
+				mu.Lock()
+				switch {
+				case err != nil:
+					result.Failed = append(result.Failed, &GenerateFailure{Path: job.rel(), Err: err})
+				case written:
+					result.Generated++
+				default:
+					result.Unchanged++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, job := range queue {
+		if ctx.Err() != nil {
+			break
+		}
+		ch <- job
+	}
+	close(ch)
+	wg.Wait()
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result.Removed, err = manifest.Prune(keep)
+	if err != nil {
+		result.Failed = append(result.Failed, &GenerateFailure{Path: "stale markdown", Err: err})
+	}
+	if err = manifest.Save(); err != nil {
+		return nil, fmt.Errorf("failed to save generate manifest: %w", err)
+	}
+	return result, nil
+}
+
+// problemMarkdownPath 题目文档相对 MarkdownDir 的路径
+func problemMarkdownPath(p *model.Problem) string {
+	return filepath.Join(p.Difficulty.String(), p.Slug+".md")
+}
+
+// contestMarkdownPath 竞赛文档相对 MarkdownDir 的路径
+func contestMarkdownPath(c *model.Contest) string {
+	return filepath.Join(c.Type.String(), c.Title+".md")
+}
+
+// tagMarkdownPath 标签文档相对 MarkdownDir 的路径
+func tagMarkdownPath(name string) string {
+	return filepath.Join("tags", strings.ReplaceAll(name, "/", "-")+".md")
+}
+
+// writeProblemMarkdown 渲染并写入题目文档，输入未变化且 force 为 false 时跳过，返回是否写入
+func writeProblemMarkdown(manifest *generator.Manifest, tpls *templates, p *model.Problem, force bool) (bool, error) {
+	problem, err := ProblemWithCode(p)
+	if err != nil {
+		return false, err
+	}
+	tpl := tpls.problem
+	rel := problemMarkdownPath(p)
+	hash, err := generator.Hash(tpl, problem)
+	if err != nil {
+		return false, err
+	}
+	if !force && manifest.Fresh(rel, hash) {
+		return false, nil
+	}
+	markdown, err := renderMarkdown(tpl, pongo2.Context{"problem": problem})
+	if err != nil {
+		return false, err
+	}
+	if err = writeMarkdownFile(rel, markdown); err != nil {
+		return false, err
+	}
+	manifest.Set(rel, hash)
+	return true, nil
+}
+
+// writeContestMarkdown 渲染并写入竞赛文档，竞赛内的题目先按题目模板渲染再交给竞赛模板
+func writeContestMarkdown(manifest *generator.Manifest, tpls *templates, c *model.Contest, force bool) (bool, error) {
+	problems := make([]*generator.Problem, 0, len(c.Problems))
+	for _, p := range c.Problems {
+		problem, err := ProblemWithCode(p)
+		if err != nil {
+			return false, err
+		}
+		problems = append(problems, problem)
+	}
+	rel := contestMarkdownPath(c)
+	hash, err := generator.Hash(tpls.contest, tpls.problem, c.Title, c.Type, problems)
+	if err != nil {
+		return false, err
+	}
+	if !force && manifest.Fresh(rel, hash) {
+		return false, nil
+	}
+	contest := &generator.Contest{
+		Title:    c.Title,
+		Type:     c.Type.String(),
+		Problems: problems,
+	}
+	for _, problem := range problems {
+		markdown, err := renderMarkdown(tpls.problem, pongo2.Context{"problem": problem})
+		if err != nil {
+			return false, err
+		}
+		contest.Sections = append(contest.Sections, markdown)
+	}
+	markdown, err := renderMarkdown(tpls.contest, pongo2.Context{"contest": contest})
+	if err != nil {
+		return false, err
+	}
+	if err = writeMarkdownFile(rel, markdown); err != nil {
+		return false, err
+	}
+	manifest.Set(rel, hash)
+	return true, nil
+}
+
+// writeTagMarkdown 渲染并写入标签文档
+func writeTagMarkdown(manifest *generator.Manifest, tpls *templates, tag *generator.Group, force bool) (bool, error) {
+	rel := tagMarkdownPath(tag.Name)
+	hash, err := generator.Hash(tpls.tag, tag)
+	if err != nil {
+		return false, err
+	}
+	if !force && manifest.Fresh(rel, hash) {
+		return false, nil
+	}
+	markdown, err := renderMarkdown(tpls.tag, pongo2.Context{"tag": tag})
+	if err != nil {
+		return false, err
+	}
+	if err = writeMarkdownFile(rel, markdown); err != nil {
+		return false, err
+	}
+	manifest.Set(rel, hash)
+	return true, nil
+}
+
+func writeMarkdownFile(rel, content string) error {
+	filePath := filepath.Join(config.GetConfig().Dir.MarkdownDir, rel)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create markdown dir: %w", err)
+	}
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write markdown file: %w", err)
+	}
+	return nil
+}
+
+// ProblemWithCode 将题目转换为模板使用的数据，并读取代码文件，待做的题目可以没有代码
+func ProblemWithCode(p *model.Problem) (*generator.Problem, error) {
+	problem := ProblemData(p)
+	if p.CodePath == "" {
+		return problem, nil
+	}
+	data, err := os.ReadFile(p.CodePath)
+	if err != nil {
+		return nil, err
+	}
+	problem.Code = &generator.Code{
+		Language: runner.LanguageName(p.CodePath),
+		Data:     string(data),
+	}
+	return problem, nil
+}
+
+// ProblemData 将题目转换为模板使用的数据，不包含代码
+func ProblemData(p *model.Problem) *generator.Problem {
+	tags := make([]string, 0)
+	for _, t := range p.Tags {
+		tags = append(tags, t.Name)
+	}
+	created := p.CreatedAt.Format("2006-01-02 15:04:05")
+	updated := p.UpdatedAt.Format("2006-01-02 15:04:05")
+	attempts := make([]*generator.Attempt, 0, len(p.Attempts))
+	for _, a := range p.Attempts {
+		attempts = append(attempts, &generator.Attempt{
+			CreatedAt: a.CreatedAt.Format("2006-01-02 15:04:05"),
+			Verdict:   a.Verdict,
+			Duration:  a.DurationText(),
+			Language:  a.Language,
+			Note:      a.Note,
+		})
+	}
+
+	return &generator.Problem{
+		Title:       p.Title,
+		Difficulty:  p.Difficulty.String(),
+		Status:      string(p.Status),
+		Tags:        tags,
+		SolutionURL: p.SolutionURL,
+		Score:       p.Score,
+		Rating:      p.Rating,
+		TimeLimit:   p.TimeLimit,
+		MemoryLimit: p.MemoryLimit,
+		CreatedAt:   created,
+		UpdatedAt:   updated,
+		Slug:        p.Slug,
+		Description: p.Description,
+		Solution:    p.Note,
+		Attempts:    attempts,
+	}
+}
+
+func renderMarkdown(tplStr string, ctx pongo2.Context) (string, error) {
+	tpl, err := generator.Compile(tplStr)
+	if err != nil {
+		return "", err
+	}
+	return tpl.Execute(ctx)
+}
+
+// GenerateHeatmap 生成热力图并写入 MarkdownDir，colors 为空时使用配置中的颜色，返回文件路径
+func (s *Service) GenerateHeatmap(ctx context.Context, colors []string) (string, error) {
+	cnf := config.GetConfig()
+	loc, err := cnf.Stat.Location()
+	if err != nil {
+		return "", err
+	}
+	solvedAt, err := s.SolvedAt(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(colors) == 0 {
+		colors = cnf.Heatmap.Colors
+	}
+
+	heatmap := &generator.Heatmap{
+		Counts:   stat.DailyCounts(solvedAt, loc),
+		End:      time.Now(),
+		Colors:   colors,
+		Location: loc,
+	}
+	if err = os.MkdirAll(cnf.Dir.MarkdownDir, 0755); err != nil {
+		return "", err
+	}
+	filePath := filepath.Join(cnf.Dir.MarkdownDir, cnf.Heatmap.FileName)
+	if err = os.WriteFile(filePath, []byte(heatmap.RenderSVG()), 0644); err != nil {
+		return "", err
+	}
+	return filePath, nil
+}
+
+// GenerateIndex 生成 MarkdownDir 下的 README.md，heatmap 为 true 时一并生成并嵌入热力图，返回文件路径
+func (s *Service) GenerateIndex(ctx context.Context, overrides map[generator.Kind]string, heatmap bool) (string, error) {
+	tpls, err := loadTemplates(overrides)
+	if err != nil {
+		return "", err
+	}
+	index, err := s.BuildIndex(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to build index: %w", err)
+	}
+	if heatmap {
+		heatmapPath, err := s.GenerateHeatmap(ctx, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate heatmap: %w", err)
+		}
+		index.Heatmap = filepath.Base(heatmapPath)
+	}
+
+	out, err := renderMarkdown(tpls.index, pongo2.Context{"index": index})
+	if err != nil {
+		return "", fmt.Errorf("failed to render index: %w", err)
+	}
+	dir := config.GetConfig().Dir.MarkdownDir
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create markdown dir: %w", err)
+	}
+	filePath := filepath.Join(dir, "README.md")
+	if err = os.WriteFile(filePath, []byte(out), 0644); err != nil {
+		return "", fmt.Errorf("failed to write index file: %w", err)
+	}
+	return filePath, nil
+}
+
+// BuildIndex 汇总已解决的题目，链接与生成的文档路径保持一致
+func (s *Service) BuildIndex(ctx context.Context) (*generator.Index, error) {
+	conn := s.db.WithContext(ctx)
+	var problems []*model.Problem
+	if err := conn.Preload("Tags").Where("status IN ?", model.SolvedStatuses).Order("id").Find(&problems).Error; err != nil {
+		return nil, err
+	}
+	var contests []*model.Contest
+	if err := conn.Order("id").Find(&contests).Error; err != nil {
+		return nil, err
+	}
+	contestByID := make(map[int64]*model.Contest, len(contests))
+	for _, c := range contests {
+		contestByID[c.ID] = c
+	}
+
+	index := &generator.Index{
+		Total:       len(problems),
+		GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
+	}
+	diffCount := make(map[model.Difficulty]int)
+	tagGroups := make(map[string]*generator.Group)
+	contestGroups := make(map[int64]*generator.Group)
+	for _, p := range problems {
+		item := &generator.IndexProblem{
+			ID:          p.ID,
+			Title:       p.Title,
+			Slug:        p.Slug,
+			Difficulty:  p.Difficulty.String(),
+			Score:       p.Score,
+			SolutionURL: p.SolutionURL,
+			Link:        problemMarkdownLink(p),
+			CreatedAt:   p.CreatedAt.Format("2006-01-02"),
+		}
+		diffCount[p.Difficulty]++
+		for _, t := range p.Tags {
+			item.Tags = append(item.Tags, t.Name)
+			group, ok := tagGroups[t.Name]
+			if !ok {
+				group = &generator.Group{Name: t.Name, Link: url.PathEscape("tags") + "/" + url.PathEscape(filepath.Base(tagMarkdownPath(t.Name)))}
+				tagGroups[t.Name] = group
+			}
+			group.Problems = append(group.Problems, item)
+		}
+		if c, ok := contestByID[p.ContestID]; ok {
+			item.Contest = c.Title
+			group, ok := contestGroups[c.ID]
+			if !ok {
+				group = &generator.Group{Name: c.Title, Type: c.Type.String(), Link: contestMarkdownLink(c)}
+				contestGroups[c.ID] = group
+			}
+			group.Problems = append(group.Problems, item)
+		}
+		index.Problems = append(index.Problems, item)
+	}
+
+	for _, d := range []model.Difficulty{model.Easy, model.Medium, model.Hard} {
+		index.Difficulties = append(index.Difficulties, &generator.Count{Name: d.String(), Count: diffCount[d]})
+	}
+	for _, group := range tagGroups {
+		index.Tags = append(index.Tags, group)
+	}
+	sort.Slice(index.Tags, func(i, j int) bool {
+		if len(index.Tags[i].Problems) != len(index.Tags[j].Problems) {
+			return len(index.Tags[i].Problems) > len(index.Tags[j].Problems)
+		}
+		return index.Tags[i].Name < index.Tags[j].Name
+	})
+	for _, c := range contests {
+		if group, ok := contestGroups[c.ID]; ok {
+			index.Contests = append(index.Contests, group)
+		}
+	}
+	return index, nil
+}
+
+// problemMarkdownLink 题目文档相对 MarkdownDir 的链接
+func problemMarkdownLink(p *model.Problem) string {
+	return url.PathEscape(p.Difficulty.String()) + "/" + url.PathEscape(p.Slug) + ".md"
+}
+
+// contestMarkdownLink 竞赛文档相对 MarkdownDir 的链接
+func contestMarkdownLink(c *model.Contest) string {
+	return url.PathEscape(c.Type.String()) + "/" + url.PathEscape(c.Title) + ".md"
+}
@@ -0,0 +1,479 @@
+package service
+
+import (
+	"algo/internal/model"
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AddInput 新增题目的参数，Code 与 CodePath 二选一
+type AddInput struct {
+	Title       string      `json:"title"`
+	Difficulty  string      `json:"difficulty"`
+	Tags        []string    `json:"tags"`
+	SolutionURL string      `json:"solution"`
+	Note        string      `json:"note"`
+	Description string      `json:"description"`
+	CodePath    string      `json:"codePath"` // 本地代码文件，会被复制到 CodeDir
+	Code        string      `json:"code"`     // 代码内容
+	Language    string      `json:"language"` // 配合 Code 使用的文件扩展名，如 cpp
+	Score       *int        `json:"score"`
+	Rating      *int        `json:"rating"` // 在线题库难度分
+	Contest     string      `json:"contest"`
+	ContestType string      `json:"contestType"`
+	TimeLimit   int         `json:"timeLimit"`   // 毫秒
+	MemoryLimit int         `json:"memoryLimit"` // MB
+	Tests       []TestInput `json:"tests"`
+	Checker     string      `json:"checker"`     // exact|tokens|float|custom
+	Epsilon     *float64    `json:"epsilon"`     // float 模式的误差
+	CheckerPath string      `json:"checkerPath"` // custom 模式的检查程序，会被复制到 CodeDir
+	Status      string      `json:"status"`      // todo|attempted|solved|needs-review，新增时默认为 solved
+}
+
+// TestInput 测试用例的输入与期望输出
+type TestInput struct {
+	Input  string `json:"input"`
+	Output string `json:"output"`
+	Sample bool   `json:"sample"`
+}
+
+// EditInput 修改题目的参数，零值字段保持不变
+type EditInput AddInput
+
+// ListFilter 题目查询条件
+type ListFilter struct {
+	Title      string
+	Difficulty string
+	Tags       []string
+	Score      *int
+	Status     string
+	Limit      int
+	Offset     int
+}
+
+// ParseScore 解析命令行或表单中的评分，空字符串返回 nil
+func ParseScore(score string) (*int, error) {
+	score = strings.TrimSpace(score)
+	if score == "" {
+		return nil, nil
+	}
+	atoi, err := strconv.Atoi(score)
+	if err != nil {
+		return nil, invalid("score", "must be a number")
+	}
+	return &atoi, nil
+}
+
+// SplitTags 按英文逗号拆分标签
+func SplitTags(tags string) []string {
+	if strings.TrimSpace(tags) == "" {
+		return nil
+	}
+	return strings.Split(tags, ",")
+}
+
+func checkScore(score *int) (*uint8, error) {
+	if score == nil {
+		return nil, nil
+	}
+	if *score < 0 || *score > 255 { // uint8 范围检查
+		return nil, invalid("score", "must be between 0 and 255")
+	}
+	v := uint8(*score)
+	return &v, nil
+}
+
+// setChecker 校验并设置答案检查方式，custom 模式需要已有或同时提供检查程序
+func setChecker(problem *model.Problem, checker string, epsilon *float64, checkerPath string) error {
+	if checker != "" {
+		mode := model.CheckerMode(checker)
+		if !mode.Valid() {
+			return invalid("checker", "must be exact|tokens|float|custom")
+		}
+		problem.Checker = mode
+	}
+	if epsilon != nil {
+		if *epsilon < 0 {
+			return invalid("epsilon", "must not be negative")
+		}
+		problem.Epsilon = *epsilon
+	}
+	if checkerPath != "" {
+		if _, err := problem.SaveAux(model.AuxChecker, checkerPath); err != nil {
+			return err
+		}
+		if checker == "" {
+			problem.Checker = model.CheckerCustom
+		}
+	}
+	if problem.Checker == model.CheckerCustom && problem.AuxPath(model.AuxChecker) == "" {
+		return invalid("checker", "custom checker requires a checker file")
+	}
+	return nil
+}
+
+func checkStatus(status string) (model.Status, error) {
+	s := model.Status(status)
+	if !s.Valid() {
+		return "", invalid("status", "must be todo|attempted|solved|needs-review")
+	}
+	return s, nil
+}
+
+func checkDifficulty(difficulty string) (model.Difficulty, error) {
+	diff := model.Difficulty(difficulty)
+	if !diff.Valid() {
+		return "", invalid("difficulty", "must be easy|medium|hard")
+	}
+	return diff, nil
+}
+
+// saveCode 复制本地代码文件或写入代码内容
+func saveCode(problem *model.Problem, codePath, code, language string) error {
+	if code != "" {
+		ext := strings.TrimPrefix(strings.TrimSpace(language), ".")
+		if ext == "" {
+			return invalid("language", "is required with code")
+		}
+		return problem.SaveCode([]byte(code), "."+ext)
+	}
+	problem.CodePath = codePath
+	return problem.CopyCode()
+}
+
+// addTests 为题目追加测试用例
+func addTests(tx *gorm.DB, problem *model.Problem, tests []TestInput, sample bool) error {
+	if len(tests) == 0 {
+		return nil
+	}
+	id := nextID(tx, &model.TestCase{})
+	cases := make([]*model.TestCase, 0, len(tests))
+	for i, t := range tests {
+		cases = append(cases, &model.TestCase{ID: id + int64(i), ProblemID: problem.ID, Input: t.Input, Output: t.Output, Sample: sample || t.Sample})
+	}
+	if err := tx.Create(cases).Error; err != nil {
+		return fmt.Errorf("failed to save tests: %w", err)
+	}
+	problem.Tests = append(problem.Tests, cases...)
+	return nil
+}
+
+// AddProblem 校验参数并新增题目，指定的竞赛不存在时一并创建
+func (s *Service) AddProblem(ctx context.Context, in *AddInput) (*model.Problem, error) {
+	if strings.TrimSpace(in.Title) == "" {
+		return nil, invalid("title", "is required")
+	}
+	diff, err := checkDifficulty(in.Difficulty)
+	if err != nil {
+		return nil, err
+	}
+	score, err := checkScore(in.Score)
+	if err != nil {
+		return nil, err
+	}
+	status := model.StatusSolved
+	if in.Status != "" {
+		if status, err = checkStatus(in.Status); err != nil {
+			return nil, err
+		}
+	}
+	// 待做的题目可以没有代码
+	if in.CodePath == "" && in.Code == "" && status != model.StatusTodo {
+		return nil, invalid("code", "is required unless status is todo")
+	}
+	if in.TimeLimit < 0 || in.MemoryLimit < 0 {
+		return nil, invalid("limit", "must not be negative")
+	}
+
+	var problem *model.Problem
+	err = s.transaction(ctx, func(tx *gorm.DB) error {
+		tags, err := ensureTags(tx, in.Tags)
+		if err != nil {
+			return err
+		}
+		problem = &model.Problem{
+			ID:          nextID(tx, &model.Problem{}),
+			Title:       strings.TrimSpace(in.Title),
+			Difficulty:  diff,
+			Tags:        tags,
+			SolutionURL: in.SolutionURL,
+			Note:        in.Note,
+			Description: in.Description,
+			Score:       score,
+			Rating:      in.Rating,
+			TimeLimit:   in.TimeLimit,
+			MemoryLimit: in.MemoryLimit,
+		}
+		problem.SetStatus(status, time.Now())
+		problem.SetSlug()
+		if in.CodePath != "" || in.Code != "" {
+			if err = saveCode(problem, in.CodePath, in.Code, in.Language); err != nil {
+				return err
+			}
+		}
+		if err = setChecker(problem, in.Checker, in.Epsilon, in.CheckerPath); err != nil {
+			return err
+		}
+		if err = tx.Create(problem).Error; err != nil {
+			return err
+		}
+		if err = addTests(tx, problem, in.Tests, true); err != nil {
+			return err
+		}
+		if in.Contest != "" {
+			return attachContest(tx, problem, in.Contest, in.ContestType)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return problem, nil
+}
+
+// EditProblem 修改题目，未设置的字段保持不变
+func (s *Service) EditProblem(ctx context.Context, slug string, in *EditInput) (*model.Problem, error) {
+	return s.editProblem(ctx, slug, in, nil)
+}
+
+// editProblem 修改题目，before 不为空时在修改前于同一事务中执行
+func (s *Service) editProblem(ctx context.Context, slug string, in *EditInput, before func(tx *gorm.DB, problem *model.Problem) error) (*model.Problem, error) {
+	var problem model.Problem
+	err := s.transaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Where("slug = ?", slug).First(&problem).Error; err != nil {
+			return notFound(err, "problem %s", slug)
+		}
+		if before != nil {
+			if err := before(tx, &problem); err != nil {
+				return err
+			}
+		}
+
+		if in.Title != "" {
+			problem.Title = in.Title
+			problem.SetSlug()
+		}
+		if in.Difficulty != "" {
+			diff, err := checkDifficulty(in.Difficulty)
+			if err != nil {
+				return err
+			}
+			problem.Difficulty = diff
+		}
+		if in.Tags != nil {
+			tags, err := ensureTags(tx, in.Tags)
+			if err != nil {
+				return err
+			}
+			// 重新添加新标签
+			if err = tx.Model(&problem).Association("Tags").Replace(tags); err != nil {
+				return fmt.Errorf("failed to update tags: %w", err)
+			}
+			problem.Tags = tags
+		}
+		if in.SolutionURL != "" {
+			problem.SolutionURL = in.SolutionURL
+		}
+		if in.Note != "" {
+			problem.Note = in.Note
+		}
+		if in.Description != "" {
+			problem.Description = in.Description
+		}
+		if in.CodePath != "" || in.Code != "" {
+			if err := saveCode(&problem, in.CodePath, in.Code, in.Language); err != nil {
+				return err
+			}
+		}
+		if in.Status != "" {
+			status, err := checkStatus(in.Status)
+			if err != nil {
+				return err
+			}
+			problem.SetStatus(status, time.Now())
+		}
+		if in.Score != nil {
+			score, err := checkScore(in.Score)
+			if err != nil {
+				return err
+			}
+			problem.Score = score
+		}
+		if in.Rating != nil {
+			problem.Rating = in.Rating
+		}
+		if in.TimeLimit > 0 {
+			problem.TimeLimit = in.TimeLimit
+		}
+		if in.MemoryLimit > 0 {
+			problem.MemoryLimit = in.MemoryLimit
+		}
+		if err := setChecker(&problem, in.Checker, in.Epsilon, in.CheckerPath); err != nil {
+			return err
+		}
+		if err := addTests(tx, &problem, in.Tests, false); err != nil {
+			return err
+		}
+		if in.Contest != "" {
+			if err := attachContest(tx, &problem, in.Contest, in.ContestType); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Omit("Tags", "Tests", "Attempts").Save(&problem).Error; err != nil {
+			return fmt.Errorf("failed to edit problem: %w", err)
+		}
+		return tx.Preload("Tags").First(&problem, problem.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &problem, nil
+}
+
+// RemoveProblem 删除题目、标签关联及代码文件
+func (s *Service) RemoveProblem(ctx context.Context, slug string) error {
+	return s.transaction(ctx, func(tx *gorm.DB) error {
+		var problem model.Problem
+		if err := tx.Where("slug = ?", slug).First(&problem).Error; err != nil {
+			return notFound(err, "problem %s", slug)
+		}
+
+		// 清空标签关联
+		if err := tx.Model(&problem).Association("Tags").Clear(); err != nil {
+			return fmt.Errorf("failed to clear tags association: %w", err)
+		}
+		// sqlite 默认不启用外键，手动删除测试用例
+		if err := tx.Where("problem_id = ?", problem.ID).Delete(&model.TestCase{}).Error; err != nil {
+			return fmt.Errorf("failed to delete tests: %w", err)
+		}
+		if err := tx.Where("problem_id = ?", problem.ID).Delete(&model.Run{}).Error; err != nil {
+			return fmt.Errorf("failed to delete runs: %w", err)
+		}
+		if err := tx.Where("problem_id = ?", problem.ID).Delete(&model.Review{}).Error; err != nil {
+			return fmt.Errorf("failed to delete review: %w", err)
+		}
+		if err := tx.Where("problem_id = ?", problem.ID).Delete(&model.ReviewLog{}).Error; err != nil {
+			return fmt.Errorf("failed to delete review logs: %w", err)
+		}
+		var attempts []*model.Attempt
+		if err := tx.Where("problem_id = ?", problem.ID).Find(&attempts).Error; err != nil {
+			return fmt.Errorf("failed to load attempts: %w", err)
+		}
+		if err := tx.Where("problem_id = ?", problem.ID).Delete(&model.Attempt{}).Error; err != nil {
+			return fmt.Errorf("failed to delete attempts: %w", err)
+		}
+
+		// 删除题目
+		if err := tx.Delete(&problem).Error; err != nil {
+			return fmt.Errorf("failed to delete problem: %w", err)
+		}
+
+		for _, a := range attempts {
+			if a.CodePath != "" {
+				if err := os.Remove(a.CodePath); err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("failed to remove code snapshot: %w", err)
+				}
+			}
+		}
+		// 先删除辅助代码，其路径依赖 CodePath
+		for _, kind := range model.AuxKinds {
+			if _, err := problem.RemoveAux(kind); err != nil {
+				return fmt.Errorf("failed to remove %s file: %w", kind, err)
+			}
+		}
+		if problem.CodePath != "" {
+			if err := os.Remove(problem.CodePath); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove code file: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// RemoveAux 删除题目的辅助代码文件，返回删除的路径，没有该文件时返回空字符串。
+// custom 模式的检查程序不能删除，需先修改检查方式
+func (s *Service) RemoveAux(ctx context.Context, slug, kind string) (string, error) {
+	if !slices.Contains(model.AuxKinds, kind) {
+		return "", invalid("kind", "must be %s", strings.Join(model.AuxKinds, "|"))
+	}
+	problem, err := s.GetProblem(ctx, slug)
+	if err != nil {
+		return "", err
+	}
+	if kind == model.AuxChecker && problem.Checker == model.CheckerCustom {
+		return "", invalid("checker", "custom checker requires a checker file")
+	}
+	return problem.RemoveAux(kind)
+}
+
+// GetProblem 按 slug 查询题目，预加载标签与做题尝试
+func (s *Service) GetProblem(ctx context.Context, slug string) (*model.Problem, error) {
+	var problem model.Problem
+	if err := s.db.WithContext(ctx).Preload("Tags").Preload("Attempts").Where("slug = ?", slug).First(&problem).Error; err != nil {
+		return nil, notFound(err, "problem %s", slug)
+	}
+	return &problem, nil
+}
+
+// PublishedProblems 导出笔记库与生成站点使用的已解决题目，按 ID 排序并预加载标签
+func (s *Service) PublishedProblems(ctx context.Context) ([]*model.Problem, error) {
+	var problems []*model.Problem
+	if err := s.db.WithContext(ctx).Preload("Tags").Where("status IN ?", model.SolvedStatuses).Order("id").Find(&problems).Error; err != nil {
+		return nil, err
+	}
+	return problems, nil
+}
+
+// ListProblems 按条件查询题目，结果按创建时间倒序并预加载标签
+func (s *Service) ListProblems(ctx context.Context, filter *ListFilter) ([]*model.Problem, error) {
+	if filter.Limit < 0 || filter.Limit > 100 {
+		return nil, invalid("limit", "must be between 0 and 100")
+	}
+	if filter.Offset < 0 {
+		return nil, invalid("offset", "must not be negative")
+	}
+	conn := s.db.WithContext(ctx)
+	query := conn.Preload("Tags").Preload("Attempts").Limit(filter.Limit).Offset(filter.Offset)
+	if filter.Difficulty != "" {
+		diff, err := checkDifficulty(filter.Difficulty)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("difficulty = ?", diff)
+	}
+	if filter.Title != "" {
+		query = query.Where("title LIKE ?", "%"+filter.Title+"%")
+	}
+	if len(filter.Tags) > 0 {
+		tags := make([]string, 0, len(filter.Tags))
+		for _, t := range filter.Tags {
+			tags = append(tags, strings.ToLower(strings.TrimSpace(t)))
+		}
+		query = query.Where("problems.id IN (?)", conn.Table("problem_tags pt").
+			Select("pt.problem_id").
+			Joins("JOIN tags t ON t.id = pt.tag_id").
+			Where("t.name IN ?", tags))
+	}
+	if filter.Score != nil {
+		query = query.Where("score = ?", *filter.Score)
+	}
+	if filter.Status != "" {
+		status, err := checkStatus(filter.Status)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("status = ?", status)
+	}
+	var problems []*model.Problem
+	if err := query.Order("created_at DESC").Find(&problems).Error; err != nil {
+		return nil, err
+	}
+	return problems, nil
+}
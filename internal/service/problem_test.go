@@ -0,0 +1,25 @@
+package service
+
+import (
+	"algo/internal/model"
+	"context"
+	"testing"
+)
+
+func TestPublishedProblems(t *testing.T) {
+	svc := newTestService(t)
+	for _, status := range []model.Status{model.StatusSolved, model.StatusTodo, model.StatusAttempted, model.StatusNeedsReview} {
+		addProblem(t, svc, &AddInput{Title: string(status), Status: string(status)})
+	}
+	problems, err := svc.PublishedProblems(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []model.Status
+	for _, p := range problems {
+		got = append(got, p.Status)
+	}
+	if len(got) != 2 || got[0] != model.StatusSolved || got[1] != model.StatusNeedsReview {
+		t.Errorf("PublishedProblems() statuses = %v, want [solved needs-review]", got)
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"algo/internal/model"
+	"algo/internal/review"
+	"algo/internal/stat"
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"sort"
+	"time"
+)
+
+// DueReview 到期待复习的题目，Review 为 nil 表示尚未复习过
+type DueReview struct {
+	Problem *model.Problem
+	Review  *model.Review
+	DueAt   time.Time
+}
+
+// RetentionBucket 一个时间段内的复习次数与记住的次数
+type RetentionBucket struct {
+	Key      string  `json:"key"`
+	Reviews  int     `json:"reviews"`
+	Recalled int     `json:"recalled"`
+	Rate     float64 `json:"rate"` // 记忆保持率，没有复习时为 0
+}
+
+// Retention 按时间统计的记忆保持率
+type Retention struct {
+	Granularity stat.Granularity  `json:"granularity"`
+	Timezone    string            `json:"timezone"`
+	Reviews     int               `json:"reviews"`
+	Recalled    int               `json:"recalled"`
+	Due         int               `json:"due"`
+	Buckets     []RetentionBucket `json:"buckets"`
+}
+
+// firstReviewDelay 尚未复习过的题目在首次解决后多久到期，与 SM-2 的第一个间隔相同
+const firstReviewDelay = 24 * time.Hour
+
+// DueReviews 返回 now 之前到期的已解决题目，按到期时间排序，limit 为 0 时不限制数量
+func (s *Service) DueReviews(ctx context.Context, now time.Time, limit int) ([]*DueReview, error) {
+	conn := s.db.WithContext(ctx)
+	var problems []*model.Problem
+	if err := conn.Preload("Tags").Where("status IN ?", model.SolvedStatuses).Order("id").Find(&problems).Error; err != nil {
+		return nil, err
+	}
+	var reviews []*model.Review
+	if err := conn.Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+	byProblem := make(map[int64]*model.Review, len(reviews))
+	for _, r := range reviews {
+		byProblem[r.ProblemID] = r
+	}
+
+	// sqlite 中时间按字符串存储，跨时区比较不可靠，因此在内存中过滤
+	due := make([]*DueReview, 0)
+	for _, p := range problems {
+		item := &DueReview{Problem: p, Review: byProblem[p.ID], DueAt: p.CreatedAt.Add(firstReviewDelay)}
+		if p.SolvedAt != nil {
+			item.DueAt = p.SolvedAt.Add(firstReviewDelay)
+		}
+		if item.Review != nil {
+			item.DueAt = item.Review.DueAt
+		}
+		if !item.DueAt.After(now) {
+			due = append(due, item)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].DueAt.Before(due[j].DueAt) })
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+// ReviewProblem 记录一次 0~5 的复习评分，按 SM-2 安排下次复习，没有记住时将题目标记为 needs-review
+func (s *Service) ReviewProblem(ctx context.Context, slug string, grade int, now time.Time) (*model.Review, error) {
+	if grade < 0 || grade > review.MaxGrade {
+		return nil, invalid("grade", "must be between 0 and %d", review.MaxGrade)
+	}
+	var r model.Review
+	err := s.transaction(ctx, func(tx *gorm.DB) error {
+		var problem model.Problem
+		if err := tx.Where("slug = ?", slug).First(&problem).Error; err != nil {
+			return notFound(err, "problem %s", slug)
+		}
+		state := review.New()
+		err := tx.Where("problem_id = ?", problem.ID).First(&r).Error
+		switch {
+		case err == nil:
+			state = review.State{Ease: r.Ease, Interval: r.Interval, Repetitions: r.Repetitions}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			r = model.Review{ID: nextID(tx, &model.Review{}), ProblemID: problem.ID}
+		default:
+			return err
+		}
+
+		state = review.SM2(state, grade)
+		r.Ease, r.Interval, r.Repetitions = state.Ease, state.Interval, state.Repetitions
+		r.ReviewedAt, r.DueAt = now, now.AddDate(0, 0, state.Interval)
+		if err = tx.Save(&r).Error; err != nil {
+			return fmt.Errorf("failed to save review: %w", err)
+		}
+		log := &model.ReviewLog{ID: nextID(tx, &model.ReviewLog{}), ProblemID: problem.ID, Grade: grade, Ease: r.Ease, Interval: r.Interval, CreatedAt: now}
+		if err = tx.Create(log).Error; err != nil {
+			return fmt.Errorf("failed to save review log: %w", err)
+		}
+		if status := problem.Status.AfterReview(review.Recalled(grade)); status != problem.Status {
+			if err = tx.Model(&problem).Update("status", status).Error; err != nil {
+				return fmt.Errorf("failed to update status: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Retention 按时间粒度统计复习次数与记忆保持率（评分不低于 3 视为记住）
+func (s *Service) Retention(ctx context.Context, q *TimelineQuery) (*Retention, error) {
+	granularity, loc, since, until, err := q.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []*model.ReviewLog
+	if err = s.db.WithContext(ctx).Order("created_at").Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	var all, recalled []time.Time
+	for _, l := range logs {
+		if !since.IsZero() && l.CreatedAt.Before(since) {
+			continue
+		}
+		// until 包含当天
+		if !until.IsZero() && !l.CreatedAt.Before(until.AddDate(0, 0, 1)) {
+			continue
+		}
+		all = append(all, l.CreatedAt)
+		if review.Recalled(l.Grade) {
+			recalled = append(recalled, l.CreatedAt)
+		}
+	}
+
+	now := time.Now()
+	due, err := s.DueReviews(ctx, now, 0)
+	if err != nil {
+		return nil, err
+	}
+	to := until
+	if to.IsZero() || to.After(now) {
+		to = now.In(loc)
+	}
+	if len(all) == 0 && since.IsZero() {
+		to = time.Time{}
+	}
+	result := &Retention{
+		Granularity: granularity,
+		Timezone:    loc.String(),
+		Reviews:     len(all),
+		Recalled:    len(recalled),
+		Due:         len(due),
+		Buckets:     make([]RetentionBucket, 0),
+	}
+	// 两组时间使用相同的起止范围分桶，按下标对应
+	from := since
+	if from.IsZero() && len(all) > 0 {
+		from = all[0]
+	}
+	recalledBuckets := stat.Buckets(recalled, granularity, loc, from, to)
+	for i, b := range stat.Buckets(all, granularity, loc, from, to) {
+		bucket := RetentionBucket{Key: b.Key, Reviews: b.Count}
+		if i < len(recalledBuckets) {
+			bucket.Recalled = recalledBuckets[i].Count
+		}
+		if bucket.Reviews > 0 {
+			bucket.Rate = float64(bucket.Recalled) / float64(bucket.Reviews)
+		}
+		result.Buckets = append(result.Buckets, bucket)
+	}
+	return result, nil
+}
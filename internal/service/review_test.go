@@ -0,0 +1,86 @@
+package service
+
+import (
+	"algo/internal/model"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestReviewProblem(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	a := addProblem(t, svc, &AddInput{Title: "A"})
+	b := addProblem(t, svc, &AddInput{Title: "B"})
+	now := time.Now()
+
+	first, err := svc.ReviewProblem(ctx, a.Slug, 5, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := svc.ReviewProblem(ctx, b.Slug, 4, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ID != 1 || second.ID != 2 {
+		t.Errorf("review IDs = %d, %d, want 1, 2", first.ID, second.ID)
+	}
+	if first.Interval != 1 || !first.DueAt.Equal(now.AddDate(0, 0, 1)) {
+		t.Errorf("first review interval %d, due %v", first.Interval, first.DueAt)
+	}
+
+	// 再次复习更新同一条计划，并追加复习记录
+	again, err := svc.ReviewProblem(ctx, a.Slug, 1, now.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != first.ID || again.Repetitions != 0 {
+		t.Errorf("review after forgetting = ID %d, repetitions %d, want ID %d, 0", again.ID, again.Repetitions, first.ID)
+	}
+	var logs []*model.ReviewLog
+	if err = svc.db.Order("id").Find(&logs).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 3 || logs[2].ID != 3 || logs[2].Grade != 1 {
+		t.Errorf("review logs = %+v, want 3 with IDs 1..3", logs)
+	}
+	problem, err := svc.GetProblem(ctx, a.Slug)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problem.Status != model.StatusNeedsReview {
+		t.Errorf("status after forgetting = %s, want %s", problem.Status, model.StatusNeedsReview)
+	}
+
+	if _, err = svc.ReviewProblem(ctx, a.Slug, 6, now); err == nil {
+		t.Error("ReviewProblem() accepted grade 6")
+	}
+}
+
+func TestDueReviews(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	todo := addProblem(t, svc, &AddInput{Title: "A", Status: string(model.StatusTodo)})
+	addProblem(t, svc, &AddInput{Title: "B", Status: string(model.StatusAttempted)})
+
+	// 首次复习时间从解决时算起，而不是从添加时算起
+	solvedAt := time.Now().AddDate(0, 0, 10)
+	if err := svc.db.Model(&model.Problem{}).Where("id = ?", todo.ID).
+		Updates(map[string]any{"status": model.StatusSolved, "solved_at": solvedAt}).Error; err != nil {
+		t.Fatal(err)
+	}
+	due, err := svc.DueReviews(ctx, solvedAt, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 0 {
+		t.Errorf("DueReviews() at the solved time = %d problems, want none", len(due))
+	}
+	due, err = svc.DueReviews(ctx, solvedAt.Add(firstReviewDelay), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 1 || due[0].Problem.ID != todo.ID || due[0].Review != nil {
+		t.Errorf("DueReviews() a day after solving = %+v, want only %s", due, todo.Slug)
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotFound 题目、标签或竞赛不存在
+	ErrNotFound = errors.New("not found")
+	// ErrConflict 名称已被占用
+	ErrConflict = errors.New("already exists")
+)
+
+// ValidationError 参数校验失败
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Message)
+}
+
+func invalid(field, format string, args ...any) error {
+	return &ValidationError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// notFound 将 gorm.ErrRecordNotFound 转换为 ErrNotFound，其余错误原样返回
+func notFound(err error, format string, args ...any) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%s %w", fmt.Sprintf(format, args...), ErrNotFound)
+	}
+	return err
+}
+
+func conflict(format string, args ...any) error {
+	return fmt.Errorf("%s %w", fmt.Sprintf(format, args...), ErrConflict)
+}
+
+// Service 题库业务逻辑，命令行、serve 与其他 Go 代码共用
+type Service struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) *Service {
+	return &Service{db: db}
+}
+
+// transaction 在事务中执行 fn
+func (s *Service) transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return s.db.WithContext(ctx).Transaction(fn)
+}
+
+// nextID 表中当前最大 ID 加一，主键均未启用自增
+func nextID(tx *gorm.DB, model any) int64 {
+	var count int64
+	tx.Model(model).Select("max(id)").Scan(&count)
+	return count + 1
+}
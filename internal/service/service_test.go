@@ -0,0 +1,67 @@
+package service
+
+import (
+	"algo/internal/model"
+	"algo/pkg/config"
+	"context"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTestService 使用临时目录中的数据库与 CodeDir
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+	dir := t.TempDir()
+	cnf := config.GetConfig()
+	saved := cnf.Dir.CodeDir
+	cnf.Dir.CodeDir = filepath.Join(dir, "code")
+	t.Cleanup(func() { cnf.Dir.CodeDir = saved })
+
+	db, err := gorm.Open(sqlite.Open("file:"+filepath.Join(dir, "algo.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	err = db.AutoMigrate(&model.Problem{}, &model.Tag{}, &model.Contest{}, &model.TestCase{}, &model.Run{}, &model.Review{}, &model.ReviewLog{}, &model.Attempt{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(db)
+}
+
+// writeFile 在临时目录中写入文件并返回路径
+func writeFile(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// addProblem 新增一道带代码的题目
+func addProblem(t *testing.T, svc *Service, in *AddInput) *model.Problem {
+	t.Helper()
+	if in.Difficulty == "" {
+		in.Difficulty = "easy"
+	}
+	if in.CodePath == "" && in.Status != string(model.StatusTodo) {
+		in.CodePath = writeFile(t, "main.cpp", "int main() {}\n")
+	}
+	problem, err := svc.AddProblem(context.Background(), in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return problem
+}
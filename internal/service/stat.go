@@ -0,0 +1,269 @@
+package service
+
+import (
+	"algo/internal/model"
+	"algo/internal/runner"
+	"algo/internal/stat"
+	"algo/pkg/config"
+	"context"
+	"database/sql"
+	"gorm.io/gorm"
+	"sort"
+	"time"
+)
+
+// StatCount 单个分组的统计结果
+type StatCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// Stat 汇总统计结果
+type Stat struct {
+	Total        int64       `json:"total"`
+	Solved       int64       `json:"solved"` // solved 与 needs-review 的题目数，以下分布只统计这些题目
+	Statuses     []StatCount `json:"statuses"`
+	Contests     int64       `json:"contests"`
+	AverageScore *float64    `json:"averageScore"`
+	Difficulties []StatCount `json:"difficulties"`
+	Tags         []StatCount `json:"tags"`
+	ContestTypes []StatCount `json:"contestTypes"`
+	Languages    []StatCount `json:"languages"`
+	Attempts     int64       `json:"attempts"`
+	Attempted    int64       `json:"attempted"`       // 有尝试记录的题目数
+	AvgAttempts  *float64    `json:"averageAttempts"` // 有尝试记录的题目平均尝试次数
+	Verdicts     []StatCount `json:"verdicts"`
+	MostAttempts []StatCount `json:"mostAttempts"` // 尝试次数最多的题目，只包含多于一次的
+}
+
+// TimelineQuery 按时间统计的条件，Since、Until 为 2006-01-02 格式，可为空
+type TimelineQuery struct {
+	Granularity string
+	Timezone    string // 为空时读取配置
+	Since       string
+	Until       string
+}
+
+// Timeline 按时间统计的结果
+type Timeline struct {
+	Granularity stat.Granularity `json:"granularity"`
+	Timezone    string           `json:"timezone"`
+	Total       int              `json:"total"`
+	Buckets     []stat.Bucket    `json:"buckets"`
+	Streak      stat.Streak      `json:"streak"`
+}
+
+// Stats 统计各状态的题目数，并按难度、标签、竞赛类型与语言统计已解决的题目
+func (s *Service) Stats(ctx context.Context) (*Stat, error) {
+	conn := s.db.WithContext(ctx)
+	solved := func() *gorm.DB {
+		return conn.Model(&model.Problem{}).Where("status IN ?", model.SolvedStatuses)
+	}
+	result := &Stat{}
+	if err := conn.Model(&model.Problem{}).Count(&result.Total).Error; err != nil {
+		return nil, err
+	}
+	if err := conn.Model(&model.Contest{}).Count(&result.Contests).Error; err != nil {
+		return nil, err
+	}
+
+	// 状态按固定顺序输出，没有题目的状态也保留
+	var statusRows []StatCount
+	if err := conn.Model(&model.Problem{}).
+		Select("status AS name, count(*) AS count").
+		Group("status").Scan(&statusRows).Error; err != nil {
+		return nil, err
+	}
+	statusCount := make(map[string]int64, len(statusRows))
+	for _, r := range statusRows {
+		statusCount[r.Name] = r.Count
+	}
+	for _, st := range model.Statuses {
+		result.Statuses = append(result.Statuses, StatCount{Name: string(st), Count: statusCount[string(st)]})
+		if st.Solved() {
+			result.Solved += statusCount[string(st)]
+		}
+	}
+
+	var avg sql.NullFloat64
+	if err := solved().Select("avg(score)").Scan(&avg).Error; err != nil {
+		return nil, err
+	}
+	if avg.Valid {
+		result.AverageScore = &avg.Float64
+	}
+
+	// 难度按固定顺序输出，没有题目的难度也保留
+	var diffRows []StatCount
+	if err := solved().
+		Select("difficulty AS name, count(*) AS count").
+		Group("difficulty").Scan(&diffRows).Error; err != nil {
+		return nil, err
+	}
+	diffCount := make(map[string]int64, len(diffRows))
+	for _, r := range diffRows {
+		diffCount[r.Name] = r.Count
+	}
+	for _, d := range []model.Difficulty{model.Easy, model.Medium, model.Hard} {
+		result.Difficulties = append(result.Difficulties, StatCount{Name: d.String(), Count: diffCount[d.String()]})
+	}
+
+	if err := conn.Table("tags t").
+		Select("t.name AS name, count(pt.problem_id) AS count").
+		Joins("JOIN problem_tags pt ON pt.tag_id = t.id").
+		Joins("JOIN problems p ON p.id = pt.problem_id").
+		Where("p.status IN ?", model.SolvedStatuses).
+		Group("t.name").Order("count DESC, name").
+		Scan(&result.Tags).Error; err != nil {
+		return nil, err
+	}
+
+	if err := conn.Table("problems p").
+		Select("c.type AS name, count(*) AS count").
+		Joins("JOIN contests c ON c.id = p.contest_id").
+		Where("p.status IN ?", model.SolvedStatuses).
+		Group("c.type").Order("count DESC, name").
+		Scan(&result.ContestTypes).Error; err != nil {
+		return nil, err
+	}
+
+	var codePaths []string
+	if err := solved().Pluck("code_path", &codePaths).Error; err != nil {
+		return nil, err
+	}
+	result.Languages = countLanguages(codePaths)
+
+	if err := conn.Model(&model.Attempt{}).Count(&result.Attempts).Error; err != nil {
+		return nil, err
+	}
+	if err := conn.Model(&model.Attempt{}).Distinct("problem_id").Count(&result.Attempted).Error; err != nil {
+		return nil, err
+	}
+	if result.Attempted > 0 {
+		avg := float64(result.Attempts) / float64(result.Attempted)
+		result.AvgAttempts = &avg
+	}
+	if err := conn.Model(&model.Attempt{}).
+		Select("verdict AS name, count(*) AS count").
+		Group("verdict").Order("count DESC, name").
+		Scan(&result.Verdicts).Error; err != nil {
+		return nil, err
+	}
+	if err := conn.Table("attempts a").
+		Select("p.slug AS name, count(*) AS count").
+		Joins("JOIN problems p ON p.id = a.problem_id").
+		Group("p.slug").Having("count(*) > 1").Order("count DESC, name").Limit(10).
+		Scan(&result.MostAttempts).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// countLanguages 按代码文件对应的语言名统计语言分布
+func countLanguages(codePaths []string) []StatCount {
+	counter := make(map[string]int64)
+	for _, p := range codePaths {
+		language := runner.LanguageName(p)
+		if language == "" {
+			language = "unknown"
+		}
+		counter[language]++
+	}
+	languages := make([]StatCount, 0, len(counter))
+	for name, count := range counter {
+		languages = append(languages, StatCount{Name: name, Count: count})
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		if languages[i].Count != languages[j].Count {
+			return languages[i].Count > languages[j].Count
+		}
+		return languages[i].Name < languages[j].Name
+	})
+	return languages
+}
+
+// Timeline 按时间粒度统计已解决题目的数量与连续天数
+func (s *Service) Timeline(ctx context.Context, q *TimelineQuery) (*Timeline, error) {
+	granularity, loc, since, until, err := q.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	// sqlite 中时间按字符串存储，跨时区比较不可靠，因此在内存中过滤
+	solvedAt, err := s.SolvedAt(ctx)
+	if err != nil {
+		return nil, err
+	}
+	times := make([]time.Time, 0, len(solvedAt))
+	for _, t := range solvedAt {
+		if !since.IsZero() && t.Before(since) {
+			continue
+		}
+		// until 包含当天
+		if !until.IsZero() && !t.Before(until.AddDate(0, 0, 1)) {
+			continue
+		}
+		times = append(times, t)
+	}
+
+	now := time.Now().In(loc)
+	to := until
+	if to.IsZero() || to.After(now) {
+		to = now
+	}
+	if len(times) == 0 && since.IsZero() {
+		to = time.Time{}
+	}
+	return &Timeline{
+		Granularity: granularity,
+		Timezone:    loc.String(),
+		Total:       len(times),
+		Buckets:     stat.Buckets(times, granularity, loc, since, to),
+		Streak:      stat.Streaks(times, loc, to),
+	}, nil
+}
+
+// SolvedAt 全部已解决题目的首次解决时间，按时间排序
+func (s *Service) SolvedAt(ctx context.Context) ([]time.Time, error) {
+	var solvedAt []time.Time
+	if err := s.db.WithContext(ctx).Model(&model.Problem{}).
+		Where("status IN ? AND solved_at IS NOT NULL", model.SolvedStatuses).
+		Order("solved_at").Pluck("solved_at", &solvedAt).Error; err != nil {
+		return nil, err
+	}
+	return solvedAt, nil
+}
+
+// parse 校验粒度、时区与起止日期
+func (q *TimelineQuery) parse() (granularity stat.Granularity, loc *time.Location, since, until time.Time, err error) {
+	if granularity, err = stat.ParseGranularity(q.Granularity); err != nil {
+		return "", nil, since, until, invalid("granularity", "%v", err)
+	}
+	if loc, err = statLocation(q.Timezone); err != nil {
+		return "", nil, since, until, invalid("timezone", "%v", err)
+	}
+	if since, err = parseStatDate(q.Since, loc); err != nil {
+		return "", nil, since, until, invalid("since", "%v", err)
+	}
+	if until, err = parseStatDate(q.Until, loc); err != nil {
+		return "", nil, since, until, invalid("until", "%v", err)
+	}
+	if !since.IsZero() && !until.IsZero() && until.Before(since) {
+		return "", nil, since, until, invalid("until", "is before since")
+	}
+	return granularity, loc, since, until, nil
+}
+
+func statLocation(timezone string) (*time.Location, error) {
+	if timezone != "" {
+		return time.LoadLocation(timezone)
+	}
+	return config.GetConfig().Stat.Location()
+}
+
+func parseStatDate(value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, loc)
+}
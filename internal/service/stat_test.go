@@ -0,0 +1,28 @@
+package service
+
+import (
+	"algo/internal/model"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTimelineUsesSolvedAt(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	problem := addProblem(t, svc, &AddInput{Title: "A"})
+	addProblem(t, svc, &AddInput{Title: "B", Status: string(model.StatusTodo)})
+
+	// 按解决时间而不是添加时间分桶
+	solvedAt := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	if err := svc.db.Model(problem).Update("solved_at", solvedAt).Error; err != nil {
+		t.Fatal(err)
+	}
+	timeline, err := svc.Timeline(ctx, &TimelineQuery{Granularity: "day", Timezone: "UTC", Since: "2024-03-04", Until: "2024-03-06"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timeline.Total != 1 || len(timeline.Buckets) != 3 || timeline.Buckets[1].Key != "2024-03-05" || timeline.Buckets[1].Count != 1 {
+		t.Errorf("Timeline() = total %d, buckets %+v, want one problem on 2024-03-05", timeline.Total, timeline.Buckets)
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"algo/internal/model"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"strings"
+)
+
+// TagCount 标签及其题目数量
+type TagCount struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Problems int64  `json:"problems"`
+}
+
+func normalizeTag(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || strings.Contains(name, ",") {
+		return "", invalid("tag", "name must be non-empty and contain no comma")
+	}
+	return name, nil
+}
+
+// ensureTags 查询标签，不存在的标签自动创建，空标签会被忽略
+func ensureTags(tx *gorm.DB, names []string) ([]*model.Tag, error) {
+	tags := make([]*model.Tag, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		var tag model.Tag
+		if err := tx.Where("name = ?", name).First(&tag).Error; err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, err
+			}
+			tag = model.Tag{ID: nextID(tx, &model.Tag{}), Name: name}
+			if err = tx.Create(&tag).Error; err != nil {
+				return nil, err
+			}
+		}
+		tags = append(tags, &tag)
+	}
+	return tags, nil
+}
+
+// ListTags 查询全部标签及题目数量，按名称排序
+func (s *Service) ListTags(ctx context.Context) ([]*TagCount, error) {
+	tags := make([]*TagCount, 0)
+	err := s.db.WithContext(ctx).Table("tags t").
+		Select("t.id AS id, t.name AS name, count(pt.problem_id) AS problems").
+		Joins("LEFT JOIN problem_tags pt ON pt.tag_id = t.id").
+		Group("t.id").Order("t.name").
+		Scan(&tags).Error
+	return tags, err
+}
+
+// CreateTag 新建标签，已存在时直接返回
+func (s *Service) CreateTag(ctx context.Context, name string) (*model.Tag, error) {
+	name, err := normalizeTag(name)
+	if err != nil {
+		return nil, err
+	}
+	var tags []*model.Tag
+	err = s.transaction(ctx, func(tx *gorm.DB) error {
+		tags, err = ensureTags(tx, []string{name})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags[0], nil
+}
+
+// RenameTag 重命名标签
+func (s *Service) RenameTag(ctx context.Context, id int64, name string) (*model.Tag, error) {
+	name, err := normalizeTag(name)
+	if err != nil {
+		return nil, err
+	}
+	var tag model.Tag
+	err = s.transaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.First(&tag, id).Error; err != nil {
+			return notFound(err, "tag %d", id)
+		}
+		var count int64
+		tx.Model(&model.Tag{}).Where("name = ? AND id <> ?", name, tag.ID).Count(&count)
+		if count > 0 {
+			return conflict("tag %s", name)
+		}
+		tag.Name = name
+		return tx.Save(&tag).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// DeleteTag 删除标签及其题目关联
+func (s *Service) DeleteTag(ctx context.Context, id int64) error {
+	return s.transaction(ctx, func(tx *gorm.DB) error {
+		var tag model.Tag
+		if err := tx.First(&tag, id).Error; err != nil {
+			return notFound(err, "tag %d", id)
+		}
+		if err := tx.Model(&tag).Association("Problems").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(&tag).Error
+	})
+}
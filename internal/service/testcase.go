@@ -0,0 +1,90 @@
+package service
+
+import (
+	"algo/internal/model"
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+)
+
+// ProblemTests 按 slug 查询题目，并按添加顺序预加载测试用例
+func (s *Service) ProblemTests(ctx context.Context, slug string) (*model.Problem, error) {
+	var problem model.Problem
+	err := s.db.WithContext(ctx).Preload("Tests", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Where("slug = ?", slug).First(&problem).Error
+	if err != nil {
+		return nil, notFound(err, "problem %s", slug)
+	}
+	return &problem, nil
+}
+
+// AddTests 为题目追加测试用例，TestInput.Sample 标记样例
+func (s *Service) AddTests(ctx context.Context, slug string, tests []TestInput) (*model.Problem, error) {
+	var problem model.Problem
+	err := s.transaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Where("slug = ?", slug).First(&problem).Error; err != nil {
+			return notFound(err, "problem %s", slug)
+		}
+		return addTests(tx, &problem, tests, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &problem, nil
+}
+
+// ImportInput 导入测试数据包的参数，Tests 为导入的用例，其余字段与 EditInput 相同
+type ImportInput struct {
+	EditInput
+	Interactor string // 交互器源码，数据库修改成功后才保存到 CodeDir
+	Replace    bool   // 导入前删除已有的测试用例
+}
+
+// ImportTests 在同一事务中删除（Replace 时）并导入测试用例、修改题目设置，返回删除的用例数
+func (s *Service) ImportTests(ctx context.Context, slug string, in *ImportInput) (*model.Problem, int64, error) {
+	var removed int64
+	problem, err := s.editProblem(ctx, slug, &in.EditInput, func(tx *gorm.DB, problem *model.Problem) error {
+		if !in.Replace {
+			return nil
+		}
+		result := tx.Where("problem_id = ?", problem.ID).Delete(&model.TestCase{})
+		removed = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if in.Interactor != "" {
+		if _, err = problem.SaveAux(model.AuxInteractor, in.Interactor); err != nil {
+			return nil, 0, fmt.Errorf("tests imported, but %w", err)
+		}
+	}
+	return problem, removed, nil
+}
+
+// SaveRuns 保存运行记录
+func (s *Service) SaveRuns(ctx context.Context, runs []*model.Run) error {
+	if len(runs) == 0 {
+		return nil
+	}
+	return s.transaction(ctx, func(tx *gorm.DB) error {
+		id := nextID(tx, &model.Run{})
+		for i, run := range runs {
+			run.ID = id + int64(i)
+		}
+		return tx.Create(runs).Error
+	})
+}
+
+// ListRuns 按时间倒序查询题目最近的运行记录
+func (s *Service) ListRuns(ctx context.Context, slug string, limit int) ([]*model.Run, error) {
+	problem, err := s.GetProblem(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	var runs []*model.Run
+	err = s.db.WithContext(ctx).Where("problem_id = ?", problem.ID).
+		Order("id DESC").Limit(limit).Find(&runs).Error
+	return runs, err
+}
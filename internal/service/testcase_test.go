@@ -0,0 +1,82 @@
+package service
+
+import (
+	"algo/internal/model"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestImportTests(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	problem := addProblem(t, svc, &AddInput{Title: "Guess", Tests: []TestInput{{Input: "1\n", Output: "1\n"}}})
+
+	in := &ImportInput{
+		EditInput: EditInput{
+			TimeLimit: 1000,
+			Tests:     []TestInput{{Input: "2\n", Output: "2\n", Sample: true}, {Input: "3\n", Output: "3\n"}},
+		},
+		Interactor: writeFile(t, "interactor.cpp", "// interactor\n"),
+		Replace:    true,
+	}
+	got, removed, err := svc.ImportTests(ctx, problem.Slug, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 1 || got.TimeLimit != 1000 {
+		t.Errorf("ImportTests() removed %d, time limit %d, want 1 and 1000", removed, got.TimeLimit)
+	}
+	if got.AuxPath(model.AuxInteractor) == "" {
+		t.Error("ImportTests() did not save the interactor")
+	}
+	loaded, err := svc.ProblemTests(ctx, problem.Slug)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Tests) != 2 || loaded.Tests[0].Input != "2\n" || !loaded.Tests[0].Sample || loaded.Tests[1].Sample {
+		t.Errorf("ImportTests() tests = %+v", loaded.Tests)
+	}
+	if loaded.Tests[0].ID >= loaded.Tests[1].ID {
+		t.Errorf("ImportTests() test IDs %d, %d, want increasing", loaded.Tests[0].ID, loaded.Tests[1].ID)
+	}
+}
+
+func TestImportTestsRollback(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	problem := addProblem(t, svc, &AddInput{Title: "Guess", Tests: []TestInput{{Input: "1\n", Output: "1\n"}}})
+
+	// custom 模式缺少检查程序，导入失败时不能删除已有用例，也不能留下交互器
+	in := &ImportInput{
+		EditInput: EditInput{
+			Checker: string(model.CheckerCustom),
+			Tests:   []TestInput{{Input: "2\n", Output: "2\n"}},
+		},
+		Interactor: writeFile(t, "interactor.cpp", "// interactor\n"),
+		Replace:    true,
+	}
+	_, _, err := svc.ImportTests(ctx, problem.Slug, in)
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("ImportTests() error = %v, want ValidationError", err)
+	}
+	loaded, err := svc.ProblemTests(ctx, problem.Slug)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Tests) != 1 || loaded.Tests[0].Input != "1\n" {
+		t.Errorf("tests after failed import = %+v, want the original case", loaded.Tests)
+	}
+	if path := loaded.AuxPath(model.AuxInteractor); path != "" {
+		t.Errorf("failed import saved interactor %s", path)
+	}
+}
+
+func TestImportTestsNotFound(t *testing.T) {
+	svc := newTestService(t)
+	_, _, err := svc.ImportTests(context.Background(), "0001_missing", &ImportInput{Replace: true})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("ImportTests() error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,147 @@
+package site
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// language 语法高亮规则
+type language struct {
+	keywords     map[string]bool
+	lineComment  []string
+	blockComment [2]string
+	quotes       string
+}
+
+func words(s string) map[string]bool {
+	m := make(map[string]bool)
+	for _, w := range strings.Fields(s) {
+		m[w] = true
+	}
+	return m
+}
+
+var cLike = "auto break case char const continue default do double else enum extern float for goto if inline int long " +
+	"register return short signed sizeof static struct switch typedef union unsigned void volatile while bool true false"
+
+var languages = map[string]*language{
+	"c": {keywords: words(cLike + " NULL"), lineComment: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: `"'`},
+	"cpp": {keywords: words(cLike + " class namespace using template typename public private protected virtual override " +
+		"new delete this nullptr operator friend constexpr noexcept try catch throw std vector string map set pair"),
+		lineComment: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: `"'`},
+	"java": {keywords: words("abstract boolean break byte case catch char class continue default do double else enum extends " +
+		"final finally float for if implements import instanceof int interface long new null package private protected public " +
+		"return short static super switch this throw throws try void while true false var"),
+		lineComment: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: `"'`},
+	"go": {keywords: words("break case chan const continue default defer else fallthrough for func go goto if import interface " +
+		"map package range return select struct switch type var true false nil int int64 string bool byte rune error make len append"),
+		lineComment: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"'`"},
+	"py": {keywords: words("and as assert async await break class continue def del elif else except False finally for from " +
+		"global if import in is lambda None nonlocal not or pass raise return True try while with yield self print range len"),
+		lineComment: []string{"#"}, quotes: `"'`},
+	"js": {keywords: words("async await break case catch class const continue debugger default delete do else export extends " +
+		"false finally for function if import in instanceof let new null return super switch this throw true try typeof " +
+		"undefined var void while yield"),
+		lineComment: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"'`"},
+	"rs": {keywords: words("as break const continue crate else enum extern false fn for if impl in let loop match mod move mut " +
+		"pub ref return self Self static struct super trait true type unsafe use where while i32 i64 u32 u64 usize String Vec"),
+		lineComment: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: `"`},
+	"kt": {keywords: words("as break class continue do else false for fun if in interface is null object package return super " +
+		"this throw true try typealias val var when while"),
+		lineComment: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: `"'`},
+}
+
+func init() {
+	for alias, name := range map[string]string{"cc": "cpp", "cxx": "cpp", "h": "c", "hpp": "cpp", "python": "py",
+		"golang": "go", "ts": "js", "javascript": "js", "typescript": "js", "rust": "rs", "kotlin": "kt"} {
+		languages[alias] = languages[name]
+	}
+}
+
+// Highlight 将代码转换为带高亮 class 的 HTML，未知语言只做转义
+func Highlight(code, lang string) string {
+	rules, ok := languages[strings.ToLower(lang)]
+	if !ok {
+		return html.EscapeString(code)
+	}
+
+	var sb strings.Builder
+	span := func(class, text string) {
+		sb.WriteString(`<span class="` + class + `">` + html.EscapeString(text) + `</span>`)
+	}
+	src := []rune(code)
+	for i := 0; i < len(src); {
+		rest := string(src[i:min(len(src), i+2)])
+		switch {
+		case rules.blockComment[0] != "" && strings.HasPrefix(rest, rules.blockComment[0]):
+			open, closing := rules.blockComment[0], rules.blockComment[1]
+			body := string(src[i+len([]rune(open)):])
+			text := string(src[i:])
+			if end := strings.Index(body, closing); end >= 0 {
+				text = open + body[:end+len(closing)]
+			}
+			span("com", text)
+			i += len([]rune(text))
+		case hasAnyPrefix(rest, rules.lineComment):
+			j := i
+			for j < len(src) && src[j] != '\n' {
+				j++
+			}
+			span("com", string(src[i:j]))
+			i = j
+		case strings.ContainsRune(rules.quotes, src[i]):
+			quote := src[i]
+			j := i + 1
+			for j < len(src) && src[j] != quote && (src[j] != '\n' || quote == '`') {
+				if src[j] == '\\' && quote != '`' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(src))
+			span("str", string(src[i:j]))
+			i = j
+		case unicode.IsDigit(src[i]):
+			j := i
+			for j < len(src) && (unicode.IsDigit(src[j]) || unicode.IsLetter(src[j]) || src[j] == '.' || src[j] == '_') {
+				j++
+			}
+			span("num", string(src[i:j]))
+			i = j
+		case unicode.IsLetter(src[i]) || src[i] == '_':
+			j := i
+			for j < len(src) && (unicode.IsLetter(src[j]) || unicode.IsDigit(src[j]) || src[j] == '_') {
+				j++
+			}
+			word := string(src[i:j])
+			if rules.keywords[word] {
+				span("kw", word)
+			} else {
+				sb.WriteString(html.EscapeString(word))
+			}
+			i = j
+		case src[i] == '#' && (i == 0 || src[i-1] == '\n') && !hasAnyPrefix("#", rules.lineComment):
+			// C/C++ 预处理指令
+			j := i
+			for j < len(src) && src[j] != '\n' {
+				j++
+			}
+			span("pre", string(src[i:j]))
+			i = j
+		default:
+			sb.WriteString(html.EscapeString(string(src[i])))
+			i++
+		}
+	}
+	return sb.String()
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
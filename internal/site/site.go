@@ -0,0 +1,225 @@
+package site
+
+import (
+	"algo/internal/generator"
+	"encoding/json"
+	"fmt"
+	"github.com/flosch/pongo2"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Problem 站点中的一道题目，Link 为相对站点根目录的页面路径
+type Problem struct {
+	*generator.Problem
+	ID       int64
+	Link     string
+	Tags     []*Ref
+	Contest  *Ref
+	CodeHTML string
+	NoteHTML string
+}
+
+// Ref 指向标签或竞赛页面的链接
+type Ref struct {
+	Name string
+	Link string
+}
+
+// Group 标签或竞赛页面
+type Group struct {
+	Name     string
+	Type     string
+	Link     string
+	Problems []*Problem
+}
+
+// Count 统计项
+type Count struct {
+	Name    string
+	Count   int64
+	Percent float64
+}
+
+// Stats 统计面板数据
+type Stats struct {
+	Total         int64
+	Contests      int64
+	AverageScore  *float64
+	Difficulties  []*Count
+	Tags          []*Count
+	Languages     []*Count
+	CurrentStreak int
+	LongestStreak int
+	HeatmapSVG    string
+	GeneratedAt   string
+}
+
+// Site 静态站点，所有页面都使用相对路径，可直接用浏览器打开
+type Site struct {
+	Dir      string
+	Title    string
+	Problems []*Problem
+	Tags     []*Group
+	Contests []*Group
+	Stats    *Stats
+}
+
+// searchEntry 客户端搜索索引中的一条记录
+type searchEntry struct {
+	Title      string   `json:"title"`
+	Slug       string   `json:"slug"`
+	Difficulty string   `json:"difficulty"`
+	Tags       []string `json:"tags"`
+	Contest    string   `json:"contest,omitempty"`
+	Link       string   `json:"link"`
+	Text       string   `json:"text"`
+}
+
+// ProblemLink 题目页面相对站点根目录的路径
+func ProblemLink(slug string) string {
+	return "problems/" + pageName(slug) + ".html"
+}
+
+// TagLink 标签页面相对站点根目录的路径
+func TagLink(name string) string {
+	return "tags/" + pageName(name) + ".html"
+}
+
+// ContestLink 竞赛页面相对站点根目录的路径
+func ContestLink(id int64) string {
+	return fmt.Sprintf("contests/%d.html", id)
+}
+
+func pageName(name string) string {
+	return strings.NewReplacer("/", "-", "\\", "-", "?", "-", "#", "-", "%", "-", " ", "-").Replace(name)
+}
+
+// Highlighted 填充高亮后的代码与笔记 HTML
+func (p *Problem) Highlighted() *Problem {
+	if p.Code != nil {
+		p.CodeHTML = Highlight(p.Code.Data, p.Code.Language)
+	}
+	p.NoteHTML = paragraphs(p.Solution)
+	return p
+}
+
+// paragraphs 将纯文本笔记按空行分段，保留换行
+func paragraphs(text string) string {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	if text == "" {
+		return ""
+	}
+	var sb strings.Builder
+	for _, block := range strings.Split(text, "\n\n") {
+		block = strings.TrimSpace(block)
+		if block == "" {
+			continue
+		}
+		sb.WriteString("<p>" + strings.ReplaceAll(html.EscapeString(block), "\n", "<br>") + "</p>\n")
+	}
+	return sb.String()
+}
+
+// Build 生成整个站点，返回写入的页面数量
+func (s *Site) Build() (int, error) {
+	pages := 0
+	render := func(rel, tpl string, ctx pongo2.Context) error {
+		root := strings.Repeat("../", strings.Count(rel, "/"))
+		ctx["root"] = root
+		ctx["site"] = s
+		body, err := execute(tpl, ctx)
+		if err != nil {
+			return fmt.Errorf("failed to render %s: %w", rel, err)
+		}
+		title, _ := ctx["title"].(string)
+		page, err := execute(layoutTemplate, pongo2.Context{"root": root, "site": s, "title": title, "body": body})
+		if err != nil {
+			return fmt.Errorf("failed to render %s: %w", rel, err)
+		}
+		pages++
+		return writeFile(filepath.Join(s.Dir, rel), page)
+	}
+
+	if err := render("index.html", indexTemplate, pongo2.Context{"title": s.Title}); err != nil {
+		return pages, err
+	}
+	for _, p := range s.Problems {
+		if err := render(p.Link, problemTemplate, pongo2.Context{"title": p.Title, "problem": p}); err != nil {
+			return pages, err
+		}
+	}
+	if err := render("tags/index.html", groupsTemplate, pongo2.Context{"title": "Tags", "groups": s.Tags}); err != nil {
+		return pages, err
+	}
+	for _, g := range s.Tags {
+		if err := render(g.Link, groupTemplate, pongo2.Context{"title": g.Name, "group": g}); err != nil {
+			return pages, err
+		}
+	}
+	if err := render("contests/index.html", groupsTemplate, pongo2.Context{"title": "Contests", "groups": s.Contests}); err != nil {
+		return pages, err
+	}
+	for _, g := range s.Contests {
+		if err := render(g.Link, groupTemplate, pongo2.Context{"title": g.Name, "group": g}); err != nil {
+			return pages, err
+		}
+	}
+	if s.Stats != nil {
+		if err := render("stats.html", statsTemplate, pongo2.Context{"title": "Stats", "stats": s.Stats}); err != nil {
+			return pages, err
+		}
+	}
+
+	if err := writeFile(filepath.Join(s.Dir, "assets", "style.css"), styleCSS); err != nil {
+		return pages, err
+	}
+	if err := writeFile(filepath.Join(s.Dir, "assets", "search.js"), searchJS); err != nil {
+		return pages, err
+	}
+	return pages, s.writeSearchIndex()
+}
+
+// writeSearchIndex 写入 JSON 搜索索引，同时写一份 JS 版本以便 file:// 下离线使用
+func (s *Site) writeSearchIndex() error {
+	entries := make([]*searchEntry, 0, len(s.Problems))
+	for _, p := range s.Problems {
+		entry := &searchEntry{
+			Title:      p.Title,
+			Slug:       p.Slug,
+			Difficulty: p.Difficulty,
+			Tags:       p.Problem.Tags,
+			Link:       p.Link,
+			Text:       strings.Join(strings.Fields(p.Description+" "+p.Solution), " "),
+		}
+		if p.Contest != nil {
+			entry.Contest = p.Contest.Name
+		}
+		entries = append(entries, entry)
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	if err = writeFile(filepath.Join(s.Dir, "search-index.json"), string(data)); err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(s.Dir, "assets", "search-index.js"), "window.ALGO_SEARCH_INDEX = "+string(data)+";\n")
+}
+
+func execute(tpl string, ctx pongo2.Context) (string, error) {
+	t, err := generator.Compile(tpl)
+	if err != nil {
+		return "", err
+	}
+	return t.Execute(ctx)
+}
+
+func writeFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
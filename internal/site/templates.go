@@ -0,0 +1,180 @@
+package site
+
+const layoutTemplate = `<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ title }}{% if title != site.Title %} · {{ site.Title }}{% endif %}</title>
+<link rel="stylesheet" href="{{ root }}assets/style.css">
+</head>
+<body>
+<header>
+  <a class="brand" href="{{ root }}index.html">{{ site.Title }}</a>
+  <nav>
+    <a href="{{ root }}index.html">题目</a>
+    <a href="{{ root }}tags/index.html">标签</a>
+    <a href="{{ root }}contests/index.html">竞赛</a>
+    {% if site.Stats %}<a href="{{ root }}stats.html">统计</a>{% endif %}
+  </nav>
+</header>
+<main>
+{{ body|safe }}
+</main>
+<footer>Generated by algo</footer>
+</body>
+</html>
+`
+
+const indexTemplate = `<h1>{{ site.Title }}</h1>
+<p class="muted">共 {{ site.Problems|length }} 道题目</p>
+<input id="search" type="search" placeholder="搜索标题、标签、竞赛或笔记…" autocomplete="off">
+<table id="problems">
+<thead><tr><th>#</th><th>题目</th><th>难度</th><th>标签</th><th>竞赛</th></tr></thead>
+<tbody>
+{% for p in site.Problems %}<tr data-slug="{{ p.Slug }}">
+  <td>{{ p.ID }}</td>
+  <td><a href="{{ root }}{{ p.Link }}">{{ p.Title }}</a></td>
+  <td><span class="badge {{ p.Difficulty }}">{{ p.Difficulty }}</span></td>
+  <td>{% for t in p.Tags %}<a class="tag" href="{{ root }}{{ t.Link }}">{{ t.Name }}</a>{% endfor %}</td>
+  <td>{% if p.Contest %}<a href="{{ root }}{{ p.Contest.Link }}">{{ p.Contest.Name }}</a>{% endif %}</td>
+</tr>
+{% endfor %}</tbody>
+</table>
+<p id="empty" class="muted" hidden>没有匹配的题目</p>
+<script src="{{ root }}assets/search-index.js"></script>
+<script src="{{ root }}assets/search.js"></script>
+`
+
+const problemTemplate = `<h1>{{ problem.Title }}</h1>
+<table class="meta">
+<tr><th>难度</th><td><span class="badge {{ problem.Difficulty }}">{{ problem.Difficulty }}</span></td></tr>
+<tr><th>标签</th><td>{% for t in problem.Tags %}<a class="tag" href="{{ root }}{{ t.Link }}">{{ t.Name }}</a>{% endfor %}</td></tr>
+{% if problem.Contest %}<tr><th>竞赛</th><td><a href="{{ root }}{{ problem.Contest.Link }}">{{ problem.Contest.Name }}</a></td></tr>{% endif %}
+{% if problem.SolutionURL %}<tr><th>链接</th><td><a href="{{ problem.SolutionURL }}">在线题目</a></td></tr>{% endif %}
+{% if problem.Score != none %}<tr><th>评分</th><td>{{ problem.Score }}</td></tr>{% endif %}
+<tr><th>创建时间</th><td>{{ problem.CreatedAt }}</td></tr>
+<tr><th>更新时间</th><td>{{ problem.UpdatedAt }}</td></tr>
+</table>
+
+<h2>📖 题目描述</h2>
+<div class="text">{{ problem.Description|default:"暂无题目描述"|linebreaksbr }}</div>
+
+<h2>💡 解题思路</h2>
+<div class="text">{% if problem.NoteHTML %}{{ problem.NoteHTML|safe }}{% else %}暂无解题思路{% endif %}</div>
+
+<h2>🛠 代码实现</h2>
+{% if problem.Code %}<pre class="code"><code class="language-{{ problem.Code.Language }}">{{ problem.CodeHTML|safe }}</code></pre>
+{% else %}<p class="muted">暂无代码实现</p>{% endif %}
+`
+
+const groupsTemplate = `<h1>{{ title }}</h1>
+<ul class="groups">
+{% for g in groups %}<li><a href="{{ root }}{{ g.Link }}">{{ g.Name }}</a>{% if g.Type %} <span class="muted">{{ g.Type }}</span>{% endif %} <span class="count">{{ g.Problems|length }}</span></li>
+{% empty %}<li class="muted">暂无</li>
+{% endfor %}</ul>
+`
+
+const groupTemplate = `<h1>{{ group.Name }}{% if group.Type %} <span class="muted">{{ group.Type }}</span>{% endif %}</h1>
+<table>
+<thead><tr><th>#</th><th>题目</th><th>难度</th><th>标签</th></tr></thead>
+<tbody>
+{% for p in group.Problems %}<tr>
+  <td>{{ p.ID }}</td>
+  <td><a href="{{ root }}{{ p.Link }}">{{ p.Title }}</a></td>
+  <td><span class="badge {{ p.Difficulty }}">{{ p.Difficulty }}</span></td>
+  <td>{% for t in p.Tags %}<a class="tag" href="{{ root }}{{ t.Link }}">{{ t.Name }}</a>{% endfor %}</td>
+</tr>
+{% endfor %}</tbody>
+</table>
+`
+
+const statsTemplate = `<h1>统计</h1>
+<div class="cards">
+  <div class="card"><b>{{ stats.Total }}</b><span>题目</span></div>
+  <div class="card"><b>{{ stats.Contests }}</b><span>竞赛</span></div>
+  <div class="card"><b>{% if stats.AverageScore != none %}{{ stats.AverageScore|floatformat:2 }}{% else %}-{% endif %}</b><span>平均评分</span></div>
+  <div class="card"><b>{{ stats.CurrentStreak }}</b><span>当前连续天数</span></div>
+  <div class="card"><b>{{ stats.LongestStreak }}</b><span>最长连续天数</span></div>
+</div>
+{% if stats.HeatmapSVG %}<div class="heatmap">{{ stats.HeatmapSVG|safe }}</div>{% endif %}
+<h2>难度分布</h2>
+{% for c in stats.Difficulties %}<div class="bar"><span class="label">{{ c.Name }}</span><span class="fill {{ c.Name }}" style="width: {{ c.Percent|floatformat:1 }}%"></span><span class="value">{{ c.Count }}</span></div>
+{% endfor %}
+<h2>标签分布</h2>
+{% for c in stats.Tags %}<div class="bar"><span class="label">{{ c.Name }}</span><span class="fill" style="width: {{ c.Percent|floatformat:1 }}%"></span><span class="value">{{ c.Count }}</span></div>
+{% empty %}<p class="muted">暂无</p>
+{% endfor %}
+<h2>语言分布</h2>
+{% for c in stats.Languages %}<div class="bar"><span class="label">{{ c.Name }}</span><span class="fill" style="width: {{ c.Percent|floatformat:1 }}%"></span><span class="value">{{ c.Count }}</span></div>
+{% empty %}<p class="muted">暂无</p>
+{% endfor %}
+<p class="muted">生成于 {{ stats.GeneratedAt }}</p>
+`
+
+const styleCSS = `:root { --fg: #1f2328; --muted: #656d76; --border: #d0d7de; --accent: #0969da; --bg: #ffffff; --code: #f6f8fa; }
+* { box-sizing: border-box; }
+body { margin: 0; font: 15px/1.6 -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; color: var(--fg); background: var(--bg); }
+header { display: flex; align-items: center; gap: 24px; padding: 12px 24px; border-bottom: 1px solid var(--border); }
+header .brand { font-weight: 600; font-size: 18px; color: var(--fg); }
+header nav a { margin-right: 16px; }
+main { max-width: 1000px; margin: 0 auto; padding: 24px; }
+footer { text-align: center; color: var(--muted); padding: 24px; font-size: 13px; }
+a { color: var(--accent); text-decoration: none; }
+a:hover { text-decoration: underline; }
+table { width: 100%; border-collapse: collapse; margin: 12px 0; }
+th, td { text-align: left; padding: 6px 10px; border-bottom: 1px solid var(--border); vertical-align: top; }
+table.meta { width: auto; }
+table.meta th { color: var(--muted); font-weight: normal; }
+.muted { color: var(--muted); }
+.tag { display: inline-block; margin: 0 4px 4px 0; padding: 0 8px; border-radius: 10px; background: #ddf4ff; font-size: 13px; }
+.badge { padding: 1px 8px; border-radius: 10px; font-size: 13px; color: #fff; background: var(--muted); }
+.badge.easy, .fill.easy { background: #1a7f37; }
+.badge.medium, .fill.medium { background: #bf8700; }
+.badge.hard, .fill.hard { background: #cf222e; }
+#search { width: 100%; padding: 8px 12px; font-size: 15px; border: 1px solid var(--border); border-radius: 6px; }
+pre.code { background: var(--code); padding: 12px 16px; border-radius: 6px; overflow: auto; font: 13px/1.5 SFMono-Regular, Consolas, Menlo, monospace; }
+pre.code .kw { color: #cf222e; }
+pre.code .str { color: #0a3069; }
+pre.code .com { color: #6e7781; font-style: italic; }
+pre.code .num { color: #0550ae; }
+pre.code .pre { color: #8250df; }
+ul.groups { list-style: none; padding: 0; columns: 3; }
+ul.groups .count { color: var(--muted); font-size: 13px; }
+.cards { display: flex; flex-wrap: wrap; gap: 12px; margin: 12px 0 24px; }
+.card { flex: 1; min-width: 120px; border: 1px solid var(--border); border-radius: 6px; padding: 12px; text-align: center; }
+.card b { display: block; font-size: 24px; }
+.card span { color: var(--muted); font-size: 13px; }
+.heatmap { overflow-x: auto; }
+.bar { display: flex; align-items: center; gap: 8px; margin: 4px 0; }
+.bar .label { width: 120px; text-align: right; overflow: hidden; text-overflow: ellipsis; white-space: nowrap; }
+.bar .fill { display: inline-block; height: 14px; min-width: 2px; background: var(--accent); border-radius: 3px; }
+.bar .value { color: var(--muted); font-size: 13px; }
+`
+
+const searchJS = `(function () {
+  var input = document.getElementById("search");
+  var table = document.getElementById("problems");
+  var empty = document.getElementById("empty");
+  if (!input || !table) return;
+  var index = {};
+  (window.ALGO_SEARCH_INDEX || []).forEach(function (e) {
+    index[e.slug] = [e.title, e.slug, e.difficulty, (e.tags || []).join(" "), e.contest || "", e.text].join(" ").toLowerCase();
+  });
+  var rows = Array.prototype.slice.call(table.tBodies[0].rows);
+  function filter() {
+    var terms = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    var shown = 0;
+    rows.forEach(function (row) {
+      var text = index[row.getAttribute("data-slug")] || row.textContent.toLowerCase();
+      var match = terms.every(function (t) { return text.indexOf(t) >= 0; });
+      row.hidden = !match;
+      if (match) shown++;
+    });
+    empty.hidden = shown > 0;
+  }
+  input.addEventListener("input", filter);
+  var q = new URLSearchParams(location.search).get("q");
+  if (q) { input.value = q; filter(); }
+})();
+`
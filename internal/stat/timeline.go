@@ -0,0 +1,173 @@
+package stat
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Granularity 时间分桶粒度
+type Granularity string
+
+const (
+	Day   Granularity = "day"
+	Week  Granularity = "week"
+	Month Granularity = "month"
+)
+
+const dayLayout = "2006-01-02"
+
+func ParseGranularity(s string) (Granularity, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "day", "d":
+		return Day, nil
+	case "week", "w":
+		return Week, nil
+	case "month", "m":
+		return Month, nil
+	default:
+		return "", fmt.Errorf("invalid granularity %q, must be day|week|month", s)
+	}
+}
+
+// Bucket 一个时间段内的做题数量
+type Bucket struct {
+	Key   string    `json:"key"`
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+// Streak 连续做题天数
+type Streak struct {
+	Current      int    `json:"current"`
+	Longest      int    `json:"longest"`
+	LongestStart string `json:"longestStart,omitempty"`
+	LongestEnd   string `json:"longestEnd,omitempty"`
+}
+
+// StartOfDay 返回 t 在 loc 时区下当天的零点
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// bucketStart 返回 t 所在时间段的起始时间，周以 ISO 周一为起点
+func bucketStart(t time.Time, g Granularity, loc *time.Location) time.Time {
+	day := StartOfDay(t, loc)
+	switch g {
+	case Week:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case Month:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, loc)
+	default:
+		return day
+	}
+}
+
+func bucketKey(start time.Time, g Granularity) string {
+	switch g {
+	case Week:
+		year, week := start.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	case Month:
+		return start.Format("2006-01")
+	default:
+		return start.Format(dayLayout)
+	}
+}
+
+func nextBucket(start time.Time, g Granularity) time.Time {
+	switch g {
+	case Week:
+		return start.AddDate(0, 0, 7)
+	case Month:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// DailyCounts 按 loc 时区的自然日统计数量，key 为 2006-01-02
+func DailyCounts(times []time.Time, loc *time.Location) map[string]int {
+	counts := make(map[string]int)
+	for _, t := range times {
+		counts[t.In(loc).Format(dayLayout)]++
+	}
+	return counts
+}
+
+// Buckets 将时间按粒度分桶，from 与 to 之间没有数据的时间段计数为 0。
+// from/to 为零值时取数据中的最早/最晚时间
+func Buckets(times []time.Time, g Granularity, loc *time.Location, from, to time.Time) []Bucket {
+	if len(times) == 0 && (from.IsZero() || to.IsZero()) {
+		return []Bucket{}
+	}
+	sorted := make([]time.Time, len(times))
+	copy(sorted, times)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+	if from.IsZero() {
+		from = sorted[0]
+	}
+	if to.IsZero() {
+		to = sorted[len(sorted)-1]
+	}
+
+	counts := make(map[string]int)
+	for _, t := range sorted {
+		counts[bucketKey(bucketStart(t, g, loc), g)]++
+	}
+
+	buckets := make([]Bucket, 0)
+	last := bucketStart(to, g, loc)
+	for cur := bucketStart(from, g, loc); !cur.After(last); cur = nextBucket(cur, g) {
+		key := bucketKey(cur, g)
+		buckets = append(buckets, Bucket{Key: key, Start: cur, Count: counts[key]})
+	}
+	return buckets
+}
+
+// Streaks 计算连续做题天数。当前连续天数以 now 所在的日期为终点，
+// 当天还没有做题时从前一天开始往回数
+func Streaks(times []time.Time, loc *time.Location, now time.Time) Streak {
+	counts := DailyCounts(times, loc)
+	streak := Streak{}
+	if len(counts) == 0 {
+		return streak
+	}
+
+	days := make([]string, 0, len(counts))
+	for day := range counts {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+
+	run := 0
+	var runStart, prev time.Time
+	for _, key := range days {
+		day, _ := time.ParseInLocation(dayLayout, key, loc)
+		if run > 0 && prev.AddDate(0, 0, 1).Equal(day) {
+			run++
+		} else {
+			run = 1
+			runStart = day
+		}
+		if run > streak.Longest {
+			streak.Longest = run
+			streak.LongestStart = runStart.Format(dayLayout)
+			streak.LongestEnd = key
+		}
+		prev = day
+	}
+
+	cur := StartOfDay(now, loc)
+	if counts[cur.Format(dayLayout)] == 0 {
+		cur = cur.AddDate(0, 0, -1)
+	}
+	for counts[cur.Format(dayLayout)] > 0 {
+		streak.Current++
+		cur = cur.AddDate(0, 0, -1)
+	}
+	return streak
+}
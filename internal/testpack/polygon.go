@@ -0,0 +1,337 @@
+package testpack
+
+import (
+	"algo/internal/model"
+	"archive/zip"
+	"cmp"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// polygonProblem Polygon 打包中 problem.xml 用到的部分
+type polygonProblem struct {
+	XMLName    xml.Name         `xml:"problem"`
+	Names      []polygonName    `xml:"names>name"`
+	Testsets   []polygonTestset `xml:"judging>testset"`
+	Checker    *polygonAsset    `xml:"assets>checker"`
+	Interactor *polygonAsset    `xml:"assets>interactor"`
+}
+
+type polygonName struct {
+	Language string `xml:"language,attr"`
+	Value    string `xml:"value,attr"`
+}
+
+type polygonTestset struct {
+	Name          string        `xml:"name,attr"`
+	TimeLimit     int           `xml:"time-limit"`   // 毫秒
+	MemoryLimit   int64         `xml:"memory-limit"` // 字节
+	TestCount     int           `xml:"test-count"`
+	InputPattern  string        `xml:"input-path-pattern"`
+	AnswerPattern string        `xml:"answer-path-pattern"`
+	Tests         []polygonTest `xml:"tests>test"`
+}
+
+type polygonTest struct {
+	Method string `xml:"method,attr"`
+	Sample bool   `xml:"sample,attr,omitempty"`
+}
+
+type polygonAsset struct {
+	Name   string         `xml:"name,attr,omitempty"`
+	Type   string         `xml:"type,attr,omitempty"`
+	Source *polygonSource `xml:"source"`
+}
+
+type polygonSource struct {
+	Path string `xml:"path,attr"`
+	Type string `xml:"type,attr"`
+}
+
+// stdCheckers testlib 标准检查程序对应的检查方式
+var stdCheckers = map[string]struct {
+	mode    model.CheckerMode
+	epsilon float64
+}{
+	"fcmp":  {model.CheckerExact, 0},
+	"wcmp":  {model.CheckerTokens, 0},
+	"lcmp":  {model.CheckerTokens, 0},
+	"ncmp":  {model.CheckerTokens, 0},
+	"uncmp": {model.CheckerTokens, 0},
+	"hcmp":  {model.CheckerTokens, 0},
+	"rcmp":  {model.CheckerFloat, 1.5e-6},
+	"acmp":  {model.CheckerFloat, 1.5e-6},
+	"dcmp":  {model.CheckerFloat, 1e-6},
+	"rcmp4": {model.CheckerFloat, 1e-4},
+	"rcmp6": {model.CheckerFloat, 1e-6},
+	"rcmp9": {model.CheckerFloat, 1e-9},
+}
+
+// sourceTypes 扩展名对应的 Polygon 语言类型
+var sourceTypes = map[string]string{
+	"c":    "c.gcc",
+	"cpp":  "cpp.g++17",
+	"java": "java11",
+	"kt":   "kotlin",
+	"py":   "python.3",
+	"go":   "go",
+	"rs":   "rust",
+}
+
+// ReadPolygon 读取 Polygon 完整打包（含生成的测试与答案）的 zip：测试、时间内存限制、检查程序、交互器与英文题面
+func ReadPolygon(name string) (*Package, error) {
+	archive, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+	files := make(map[string]*zip.File, len(archive.File))
+	xmlName := ""
+	for _, f := range archive.File {
+		files[f.Name] = f
+		// 兼容 problem.xml 位于子目录中的压缩包
+		if path.Base(f.Name) == "problem.xml" && (xmlName == "" || len(f.Name) < len(xmlName)) {
+			xmlName = f.Name
+		}
+	}
+	root := strings.TrimSuffix(xmlName, "problem.xml")
+	read := func(name string) ([]byte, error) {
+		f, ok := files[root+name]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+
+	data, err := read("problem.xml")
+	if err != nil {
+		return nil, fmt.Errorf("read problem.xml: %w", err)
+	}
+	var problem polygonProblem
+	if err = xml.Unmarshal(data, &problem); err != nil {
+		return nil, fmt.Errorf("parse problem.xml: %w", err)
+	}
+	pkg := &Package{}
+	language := "english"
+	for i, n := range problem.Names {
+		if i == 0 || n.Language == "english" {
+			pkg.Title, language = n.Value, n.Language
+		}
+	}
+
+	if len(problem.Testsets) == 0 {
+		return nil, errors.New("problem.xml has no testset")
+	}
+	testset := problem.Testsets[0]
+	for _, ts := range problem.Testsets {
+		if ts.Name == "tests" {
+			testset = ts
+		}
+	}
+	pkg.TimeLimit, pkg.MemoryLimit = testset.TimeLimit, int(testset.MemoryLimit>>20)
+	count := max(testset.TestCount, len(testset.Tests))
+	for i := 1; i <= count; i++ {
+		input, err := read(fmt.Sprintf(testset.InputPattern, i))
+		if err != nil {
+			pkg.Skipped++
+			continue
+		}
+		answer, err := read(fmt.Sprintf(testset.AnswerPattern, i))
+		if err != nil {
+			pkg.Skipped++
+			continue
+		}
+		sample := i <= len(testset.Tests) && testset.Tests[i-1].Sample
+		pkg.Cases = append(pkg.Cases, Case{Name: fmt.Sprintf("%02d", i), Input: string(input), Output: string(answer), Sample: sample})
+	}
+
+	if c := problem.Checker; c != nil {
+		name := strings.TrimSuffix(strings.TrimPrefix(c.Name, "std::"), path.Ext(c.Name))
+		if std, ok := stdCheckers[name]; ok && strings.HasPrefix(c.Name, "std::") {
+			pkg.Checker, pkg.Epsilon = std.mode, std.epsilon
+		} else if pkg.CheckerSrc, err = readSource(c, read); err != nil {
+			return nil, fmt.Errorf("read checker: %w", err)
+		} else if pkg.CheckerSrc != nil {
+			pkg.Checker = model.CheckerCustom
+		}
+	}
+	if problem.Interactor != nil {
+		if pkg.Interactor, err = readSource(problem.Interactor, read); err != nil {
+			return nil, fmt.Errorf("read interactor: %w", err)
+		}
+	}
+	pkg.Description = polygonStatement(language, read)
+	return pkg, nil
+}
+
+func readSource(asset *polygonAsset, read func(string) ([]byte, error)) (*Source, error) {
+	if asset.Source == nil || asset.Source.Path == "" {
+		return nil, nil
+	}
+	data, err := read(asset.Source.Path)
+	if err != nil {
+		return nil, err
+	}
+	return &Source{Name: path.Base(asset.Source.Path), Data: data}, nil
+}
+
+// polygonStatement 由题面各节拼成 markdown，优先读取 statement-sections，其次 problem-properties.json
+func polygonStatement(language string, read func(string) ([]byte, error)) string {
+	sections := make(map[string]string)
+	for _, name := range []string{"legend", "input", "output", "notes"} {
+		if data, err := read("statement-sections/" + language + "/" + name + ".tex"); err == nil {
+			sections[name] = string(data)
+		}
+	}
+	if len(sections) == 0 {
+		var properties struct{ Legend, Input, Output, Notes string }
+		if data, err := read("statements/" + language + "/problem-properties.json"); err == nil && json.Unmarshal(data, &properties) == nil {
+			sections = map[string]string{"legend": properties.Legend, "input": properties.Input, "output": properties.Output, "notes": properties.Notes}
+		}
+	}
+	var sb strings.Builder
+	for _, s := range []struct{ key, title string }{{"legend", ""}, {"input", "Input"}, {"output", "Output"}, {"notes", "Note"}} {
+		text := strings.TrimSpace(sections[s.key])
+		if text == "" {
+			continue
+		}
+		if s.title != "" {
+			sb.WriteString("## " + s.title + "\n\n")
+		}
+		// Polygon 用 $$$ 包裹行内公式
+		sb.WriteString(strings.ReplaceAll(text, "$$$", "$") + "\n\n")
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// Polygon 必须给出时间与内存限制，题目未记录时使用与 algo test 相同的默认值
+const (
+	defaultTimeLimit   = 2000 // 毫秒
+	defaultMemoryLimit = 256  // MB
+)
+
+// WritePolygon 写出 Polygon 格式的 zip：problem.xml、tests/NN 与 tests/NN.a、检查程序、交互器与题面。
+// 非 custom 的检查方式只引用对应的 testlib 标准检查程序，不附带源码；题面原样写入 legend.tex
+func WritePolygon(name string, pkg *Package) (err error) {
+	out, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	archive := zip.NewWriter(out)
+	write := func(name string, data []byte) error {
+		w, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+
+	testset := polygonTestset{
+		Name:          "tests",
+		TimeLimit:     cmp.Or(pkg.TimeLimit, defaultTimeLimit),
+		MemoryLimit:   int64(cmp.Or(pkg.MemoryLimit, defaultMemoryLimit)) << 20,
+		TestCount:     len(pkg.Cases),
+		InputPattern:  "tests/%02d",
+		AnswerPattern: "tests/%02d.a",
+	}
+	examples := 0
+	for i, c := range pkg.Cases {
+		testset.Tests = append(testset.Tests, polygonTest{Method: "manual", Sample: c.Sample})
+		if err = write(fmt.Sprintf(testset.InputPattern, i+1), []byte(c.Input)); err != nil {
+			return err
+		}
+		if err = write(fmt.Sprintf(testset.AnswerPattern, i+1), []byte(c.Output)); err != nil {
+			return err
+		}
+		if c.Sample {
+			examples++
+			if err = write(fmt.Sprintf("statement-sections/english/example.%02d", examples), []byte(c.Input)); err != nil {
+				return err
+			}
+			if err = write(fmt.Sprintf("statement-sections/english/example.%02d.a", examples), []byte(c.Output)); err != nil {
+				return err
+			}
+		}
+	}
+	problem := polygonProblem{
+		Names:    []polygonName{{Language: "english", Value: pkg.Title}},
+		Testsets: []polygonTestset{testset},
+		Checker:  &polygonAsset{Name: stdCheckerName(pkg), Type: "testlib"},
+	}
+	if pkg.Checker == model.CheckerCustom && pkg.CheckerSrc != nil {
+		problem.Checker.Name = ""
+		if problem.Checker.Source, err = writeSource("check", pkg.CheckerSrc, write); err != nil {
+			return err
+		}
+	}
+	if pkg.Interactor != nil {
+		problem.Interactor = &polygonAsset{}
+		if problem.Interactor.Source, err = writeSource("interactor", pkg.Interactor, write); err != nil {
+			return err
+		}
+	}
+	if err = write("statement-sections/english/name.tex", []byte(pkg.Title)); err != nil {
+		return err
+	}
+	if err = write("statement-sections/english/legend.tex", []byte(pkg.Description)); err != nil {
+		return err
+	}
+
+	data, err := xml.MarshalIndent(problem, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err = write("problem.xml", append([]byte(xml.Header), data...)); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+func writeSource(name string, src *Source, write func(string, []byte) error) (*polygonSource, error) {
+	ext := path.Ext(src.Name)
+	p := "files/" + name + ext
+	if err := write(p, src.Data); err != nil {
+		return nil, err
+	}
+	lang := strings.TrimPrefix(ext, ".")
+	if t, ok := sourceTypes[lang]; ok {
+		lang = t
+	}
+	return &polygonSource{Path: p, Type: lang}, nil
+}
+
+// stdCheckerName 与检查方式最接近的 testlib 标准检查程序
+func stdCheckerName(pkg *Package) string {
+	switch pkg.Checker {
+	case model.CheckerTokens:
+		return "std::wcmp.cpp"
+	case model.CheckerFloat:
+		switch {
+		case pkg.Epsilon >= 1e-4:
+			return "std::rcmp4.cpp"
+		case pkg.Epsilon > 0 && pkg.Epsilon < 1e-6:
+			return "std::rcmp9.cpp"
+		default:
+			return "std::rcmp6.cpp"
+		}
+	default:
+		return "std::fcmp.cpp"
+	}
+}
@@ -0,0 +1,98 @@
+package testpack
+
+import (
+	"algo/internal/model"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPolygonRoundTrip(t *testing.T) {
+	cases := []Case{
+		{Input: "1 2\n", Output: "3\n", Sample: true},
+		{Input: "2 2\n", Output: "4\n"},
+		{Input: "0 0\n", Output: "0\n", Sample: true},
+	}
+	tests := []struct {
+		name        string
+		pkg         Package
+		wantChecker model.CheckerMode
+		wantEpsilon float64
+	}{
+		{"exact", Package{Checker: model.CheckerExact}, model.CheckerExact, 0},
+		{"unset", Package{}, model.CheckerExact, 0},
+		{"tokens", Package{Checker: model.CheckerTokens}, model.CheckerTokens, 0},
+		{"float default", Package{Checker: model.CheckerFloat}, model.CheckerFloat, 1e-6},
+		{"float 1e-6", Package{Checker: model.CheckerFloat, Epsilon: 1e-6}, model.CheckerFloat, 1e-6},
+		{"float 1e-9", Package{Checker: model.CheckerFloat, Epsilon: 1e-9}, model.CheckerFloat, 1e-9},
+		{"float 1e-4", Package{Checker: model.CheckerFloat, Epsilon: 1e-4}, model.CheckerFloat, 1e-4},
+		{"float 1e-5 rounds to tighter", Package{Checker: model.CheckerFloat, Epsilon: 1e-5}, model.CheckerFloat, 1e-6},
+		{"custom", Package{
+			Checker:    model.CheckerCustom,
+			CheckerSrc: &Source{Name: "0001_a_checker.cpp", Data: []byte("// checker\n")},
+		}, model.CheckerCustom, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := tt.pkg
+			in.Title = "A + B"
+			in.Description = "Print $a + b$."
+			in.TimeLimit, in.MemoryLimit = 1500, 128
+			in.Cases = cases
+			in.Interactor = &Source{Name: "0001_a_interactor.py", Data: []byte("# interactor\n")}
+			path := filepath.Join(t.TempDir(), "a.zip")
+			if err := WritePolygon(path, &in); err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := ReadPolygon(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.Title != in.Title || out.TimeLimit != 1500 || out.MemoryLimit != 128 || out.Skipped != 0 {
+				t.Errorf("ReadPolygon() = title %q, limits %d/%d, skipped %d", out.Title, out.TimeLimit, out.MemoryLimit, out.Skipped)
+			}
+			if out.Description != in.Description {
+				t.Errorf("ReadPolygon() description = %q, want %q", out.Description, in.Description)
+			}
+			if out.Checker != tt.wantChecker || out.Epsilon != tt.wantEpsilon {
+				t.Errorf("ReadPolygon() checker = %s %g, want %s %g", out.Checker, out.Epsilon, tt.wantChecker, tt.wantEpsilon)
+			}
+			if tt.pkg.CheckerSrc != nil {
+				if out.CheckerSrc == nil || string(out.CheckerSrc.Data) != "// checker\n" || filepath.Ext(out.CheckerSrc.Name) != ".cpp" {
+					t.Errorf("ReadPolygon() checker source = %+v", out.CheckerSrc)
+				}
+			} else if out.CheckerSrc != nil {
+				t.Errorf("ReadPolygon() checker source = %+v, want none for a standard checker", out.CheckerSrc)
+			}
+			if out.Interactor == nil || string(out.Interactor.Data) != "# interactor\n" || filepath.Ext(out.Interactor.Name) != ".py" {
+				t.Errorf("ReadPolygon() interactor = %+v", out.Interactor)
+			}
+			var got []Case
+			for _, c := range out.Cases {
+				got = append(got, Case{Input: c.Input, Output: c.Output, Sample: c.Sample})
+			}
+			if !reflect.DeepEqual(got, cases) {
+				t.Errorf("ReadPolygon() cases = %+v, want %+v", got, cases)
+			}
+		})
+	}
+}
+
+func TestStdCheckers(t *testing.T) {
+	// 写出的标准检查程序都能被读回
+	for _, pkg := range []Package{
+		{Checker: model.CheckerExact},
+		{Checker: model.CheckerTokens},
+		{Checker: model.CheckerFloat, Epsilon: 1e-4},
+		{Checker: model.CheckerFloat},
+		{Checker: model.CheckerFloat, Epsilon: 1e-9},
+	} {
+		name := stdCheckerName(&pkg)
+		base := name[len("std::") : len(name)-len(filepath.Ext(name))]
+		std, ok := stdCheckers[base]
+		if !ok || std.mode != pkg.Checker {
+			t.Errorf("stdCheckerName(%s, %g) = %s, which reads back as %v", pkg.Checker, pkg.Epsilon, name, std.mode)
+		}
+	}
+}
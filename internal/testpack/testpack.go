@@ -0,0 +1,193 @@
+// Package testpack 读写其他工具的测试数据包：online-judge-tools 的目录布局与 Codeforces Polygon 打包
+package testpack
+
+import (
+	"algo/internal/model"
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// 支持的格式
+const (
+	OJ      = "oj"      // test/sample-1.in、test/sample-1.out
+	Polygon = "polygon" // Polygon 完整打包的 zip
+)
+
+// Formats 全部格式
+var Formats = []string{OJ, Polygon}
+
+// Case 一个测试用例
+type Case struct {
+	Name   string
+	Input  string
+	Output string
+	Sample bool
+}
+
+// Source 检查程序或交互器的源码，Name 带扩展名
+type Source struct {
+	Name string
+	Data []byte
+}
+
+// Package 测试数据包，oj 格式只包含测试用例
+type Package struct {
+	Title       string
+	Description string // markdown
+	TimeLimit   int    // 毫秒
+	MemoryLimit int    // MB
+	Cases       []Case
+	Checker     model.CheckerMode // 为空表示未指定
+	Epsilon     float64
+	CheckerSrc  *Source // Checker 为 custom 时的源码
+	Interactor  *Source
+	Skipped     int // 缺少答案而跳过的用例数
+}
+
+// Detect 按路径推断格式：zip 文件为 polygon，其余为 oj
+func Detect(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		return Polygon
+	}
+	return OJ
+}
+
+// Read 按格式读取测试数据包
+func Read(format, path string) (*Package, error) {
+	switch format {
+	case OJ:
+		return ReadOJ(path)
+	case Polygon:
+		return ReadPolygon(path)
+	default:
+		return nil, fmt.Errorf("unknown format %q, must be one of %s", format, strings.Join(Formats, "|"))
+	}
+}
+
+// Write 按格式写出测试数据包
+func Write(format, path string, pkg *Package) error {
+	switch format {
+	case OJ:
+		return WriteOJ(path, pkg)
+	case Polygon:
+		return WritePolygon(path, pkg)
+	default:
+		return fmt.Errorf("unknown format %q, must be one of %s", format, strings.Join(Formats, "|"))
+	}
+}
+
+// ReadOJ 读取目录中成对的 <name>.in 与 <name>.out，名称以 sample 开头的视为样例
+func ReadOJ(dir string) (*Package, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	pkg := &Package{}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".in")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		input, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		output, err := os.ReadFile(filepath.Join(dir, name+".out"))
+		if os.IsNotExist(err) {
+			pkg.Skipped++
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		pkg.Cases = append(pkg.Cases, Case{Name: name, Input: string(input), Output: string(output), Sample: strings.HasPrefix(name, "sample")})
+	}
+	slices.SortFunc(pkg.Cases, func(a, b Case) int { return compareNames(a.Name, b.Name) })
+	return pkg, nil
+}
+
+// WriteOJ 将样例写为 sample-N，其余用例写为 test-N
+func WriteOJ(dir string, pkg *Package) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	samples, tests := 0, 0
+	for _, c := range pkg.Cases {
+		var name string
+		if c.Sample {
+			samples++
+			name = "sample-" + strconv.Itoa(samples)
+		} else {
+			tests++
+			name = "test-" + strconv.Itoa(tests)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name+".in"), []byte(c.Input), 0644); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, name+".out"), []byte(c.Output), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compareNames 按前缀与末尾数字排序，sample-2 排在 sample-10 之前
+func compareNames(a, b string) int {
+	pa, na := splitNumber(a)
+	pb, nb := splitNumber(b)
+	if c := strings.Compare(pa, pb); c != 0 {
+		return c
+	}
+	return cmp.Compare(na, nb)
+}
+
+func splitNumber(name string) (string, int) {
+	i := len(name)
+	for i > 0 && name[i-1] >= '0' && name[i-1] <= '9' {
+		i--
+	}
+	n, _ := strconv.Atoi(name[i:])
+	return name[:i], n
+}
+
+// FromProblem 由题目、已加载的测试用例与辅助代码生成测试数据包
+func FromProblem(problem *model.Problem) (*Package, error) {
+	pkg := &Package{
+		Title:       problem.Title,
+		Description: problem.Description,
+		TimeLimit:   problem.TimeLimit,
+		MemoryLimit: problem.MemoryLimit,
+		Checker:     problem.Checker,
+		Epsilon:     problem.Epsilon,
+	}
+	for _, tc := range problem.Tests {
+		pkg.Cases = append(pkg.Cases, Case{Name: strconv.FormatInt(tc.ID, 10), Input: tc.Input, Output: tc.Output, Sample: tc.Sample})
+	}
+	var err error
+	if problem.Checker == model.CheckerCustom {
+		if pkg.CheckerSrc, err = readAux(problem, model.AuxChecker); err != nil {
+			return nil, err
+		}
+	}
+	if pkg.Interactor, err = readAux(problem, model.AuxInteractor); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+func readAux(problem *model.Problem, kind string) (*Source, error) {
+	path := problem.AuxPath(kind)
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Source{Name: filepath.Base(path), Data: data}, nil
+}
@@ -0,0 +1,63 @@
+package testpack
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestOJRoundTrip(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "test")
+	in := &Package{Cases: []Case{
+		{Input: "1\n", Output: "1\n", Sample: true},
+		{Input: "2\n", Output: "2\n"},
+		{Input: "3\n", Output: "3\n", Sample: true},
+	}}
+	if err := WriteOJ(dir, in); err != nil {
+		t.Fatal(err)
+	}
+	// 缺少答案的用例被跳过
+	if err := os.WriteFile(filepath.Join(dir, "extra.in"), []byte("4\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := ReadOJ(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Case{
+		{Name: "sample-1", Input: "1\n", Output: "1\n", Sample: true},
+		{Name: "sample-2", Input: "3\n", Output: "3\n", Sample: true},
+		{Name: "test-1", Input: "2\n", Output: "2\n"},
+	}
+	if !reflect.DeepEqual(out.Cases, want) || out.Skipped != 1 {
+		t.Errorf("ReadOJ() = %+v, skipped %d, want %+v, skipped 1", out.Cases, out.Skipped, want)
+	}
+}
+
+func TestCompareNames(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"sample-2", "sample-10", -1},
+		{"sample-10", "sample-2", 1},
+		{"sample-1", "test-1", -1},
+		{"01", "2", -1},
+		{"a", "a", 0},
+	}
+	for _, tt := range tests {
+		if got := compareNames(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareNames(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	for path, want := range map[string]string{"a.zip": Polygon, "A.ZIP": Polygon, "test": OJ, "tests/": OJ} {
+		if got := Detect(path); got != want {
+			t.Errorf("Detect(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -3,6 +3,11 @@ package main
 import (
 	"algo/cmd"
 	"github.com/spf13/cobra"
+	"os"
+
+	// 注册在线题库，新增题库时在此导入
+	_ "algo/internal/importer/codeforces"
+	_ "algo/internal/importer/leetcode"
 )
 
 func main() {
@@ -16,5 +21,18 @@ func main() {
 	rootCmd.AddCommand(cmd.InitRemoveCmd())
 	rootCmd.AddCommand(cmd.InitEditCmd())
 	rootCmd.AddCommand(cmd.InitGenCmd())
-	_ = rootCmd.Execute()
+	rootCmd.AddCommand(cmd.InitStatCmd())
+	rootCmd.AddCommand(cmd.InitExportCmd())
+	rootCmd.AddCommand(cmd.InitSiteCmd())
+	rootCmd.AddCommand(cmd.InitServeCmd())
+	rootCmd.AddCommand(cmd.InitListenCmd())
+	rootCmd.AddCommand(cmd.InitTestCmd())
+	rootCmd.AddCommand(cmd.InitStressCmd())
+	rootCmd.AddCommand(cmd.InitTestsCmd())
+	rootCmd.AddCommand(cmd.InitReviewCmd())
+	rootCmd.AddCommand(cmd.InitAttemptCmd())
+	rootCmd.AddCommand(cmd.InitDoctorCmd())
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
 }
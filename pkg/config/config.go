@@ -10,10 +10,19 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 )
 
 type Config struct {
-	Dir Dir `toml:"dir"`
+	Dir        Dir                 `toml:"dir"`
+	Stat       Stat                `toml:"stat"`
+	Heatmap    Heatmap             `toml:"heatmap"`
+	Template   Template            `toml:"template"`
+	LeetCode   LeetCode            `toml:"leetcode"`
+	Codeforces Codeforces          `toml:"codeforces"`
+	LeetCodeCN LeetCodeCN          `toml:"leetcode_cn"`
+	Listen     Listen              `toml:"listen"`
+	Languages  map[string]Language `toml:"languages"` // 按代码扩展名配置，覆盖内置的同名语言
 }
 
 type Dir struct {
@@ -21,6 +30,8 @@ type Dir struct {
 	NotesDir    string `toml:"notes_dir" default:"~/algo/notes"`
 	Datasource  string `toml:"datasource" default:"~/algo/db"`
 	MarkdownDir string `toml:"markdown_dir" default:"~/algo/markdown"`
+	TemplateDir string `toml:"template_dir" default:"~/algo/templates"`
+	SiteDir     string `toml:"site_dir" default:"~/algo/site"`
 }
 
 func (d *Dir) ExpandHome(home string) {
@@ -36,6 +47,59 @@ func (d *Dir) ExpandHome(home string) {
 	}
 }
 
+type Stat struct {
+	Timezone string `toml:"timezone" default:"Local"` // 统计使用的时区，IANA 名称
+}
+
+// Location 返回统计所使用的时区
+func (s *Stat) Location() (*time.Location, error) {
+	return time.LoadLocation(s.Timezone)
+}
+
+type Heatmap struct {
+	FileName string   `toml:"file_name" default:"heatmap.svg"`                                                // 输出到 MarkdownDir 下的文件名
+	Colors   []string `toml:"colors" default:"[\"#ebedf0\",\"#9be9a8\",\"#40c463\",\"#30a14e\",\"#216e39\"]"` // 由浅到深的颜色，第一个表示没有做题
+}
+
+// Template 各类文档使用的模板文件，相对 TemplateDir 或绝对路径，文件不存在时使用内置模板
+type Template struct {
+	Problem string `toml:"problem" default:"problem.md"`
+	Contest string `toml:"contest" default:"contest.md"`
+	Index   string `toml:"index" default:"index.md"`
+	Tag     string `toml:"tag" default:"tag.md"`
+}
+
+// Language 编译与运行命令模板，支持 {src} {bin} {dir} {name} 占位符
+type Language struct {
+	Name           string  `toml:"name"`    // Markdown 代码块与统计使用的语言名，默认为扩展名
+	Compile        string  `toml:"compile"` // 为空表示解释执行
+	Run            string  `toml:"run"`
+	Source         string  `toml:"source"`          // 源文件需要的固定文件名，如 Main.java
+	TimeMultiplier float64 `toml:"time_multiplier"` // 时间限制倍数，默认为 1
+}
+
+// Listen algo listen 的默认参数，TemplateDir 下存在 code.<语言> 时作为代码模板
+type Listen struct {
+	Addr       string `toml:"addr" default:"127.0.0.1:27121"` // Competitive Companion 的默认端口
+	Language   string `toml:"language" default:"cpp"`
+	Difficulty string `toml:"difficulty" default:"medium"`
+}
+
+// LeetCode add --from 使用的 LeetCode 接口地址，可指向本地测试服务
+type LeetCode struct {
+	BaseURL string `toml:"base_url" default:"https://leetcode.com"`
+}
+
+// LeetCodeCN add --from 使用的力扣中国站接口地址，可指向本地测试服务
+type LeetCodeCN struct {
+	BaseURL string `toml:"base_url" default:"https://leetcode.cn"`
+}
+
+// Codeforces add --from 使用的 Codeforces API 地址，可指向本地测试服务
+type Codeforces struct {
+	BaseURL string `toml:"base_url" default:"https://codeforces.com"`
+}
+
 var cnf *Config
 var once sync.Once
 
@@ -49,7 +113,7 @@ func initConfig() {
 		panic(err)
 	}
 	data, err := os.ReadFile(home + "/algo/algo.toml")
-	if err != nil {
+	if err == nil {
 		if err = toml.Unmarshal(data, cnf); err != nil {
 			util.GetLog().Error("failed to unmarshal config file", zap.Error(err))
 			panic("failed to unmarshal config file")